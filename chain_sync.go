@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/buger/jsonparser"
 	"github.com/gorilla/websocket"
 	"golang.org/x/sync/errgroup"
 
@@ -63,10 +64,14 @@ type ChainSyncFunc func(ctx context.Context, data []byte) error
 
 // ChainSyncOptions configuration parameters
 type ChainSyncOptions struct {
-	minSlot   uint64           // minSlot to begin invoking ChainSyncFunc; 0 for always invoke func
-	points    chainsync.Points // points to attempt initial intersection
-	reconnect bool             // reconnect to ogmios if connection drops
-	store     Store            // store of points
+	lease         Lease            // lease that must be held while syncing; nil for no leader election
+	leaseInterval time.Duration    // how frequently to renew the lease
+	minSlot       uint64           // minSlot to begin invoking ChainSyncFunc; 0 for always invoke func
+	points        chainsync.Points // points to attempt initial intersection
+	reconnect     bool             // reconnect to ogmios if connection drops
+	store         Store            // store of points
+	verifyTxIDs   bool             // recompute tx ids from the raw transactions
+	onTxMismatch  TxMismatchFunc   // invoked for each tx that fails verification; nil to stop
 }
 
 func buildChainSyncOptions(opts ...ChainSyncOption) ChainSyncOptions {
@@ -77,12 +82,30 @@ func buildChainSyncOptions(opts ...ChainSyncOption) ChainSyncOptions {
 	if options.store == nil {
 		options.store = nopStore{}
 	}
+	if options.leaseInterval <= 0 {
+		options.leaseInterval = 5 * time.Second
+		if lease, ok := options.lease.(TTLLease); ok {
+			options.leaseInterval = lease.TTL() / 3
+		}
+	}
 	return options
 }
 
 // ChainSyncOption provides functional options for ChainSync
 type ChainSyncOption func(opts *ChainSyncOptions)
 
+// WithLease requires the lease to be held while syncing.  ChainSync waits until the
+// lease is acquired, renews it every interval, and stops if the lease is lost.
+// interval must be shorter than the lease ttl or the lease may expire between
+// renewals; ChainSync returns an error if a TTLLease has a ttl <= interval.
+// interval defaults to a third of the ttl for a TTLLease, otherwise 5s
+func WithLease(lease Lease, interval time.Duration) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.lease = lease
+		opts.leaseInterval = interval
+	}
+}
+
 // WithMinSlot ignores any activity prior to the specified slot
 func WithMinSlot(slot uint64) ChainSyncOption {
 	return func(opts *ChainSyncOptions) {
@@ -111,11 +134,28 @@ func WithStore(store Store) ChainSyncOption {
 	}
 }
 
+// TxMismatchFunc is invoked with a transaction that failed verification and
+// the reason.  Returning an error stops the ChainSync
+type TxMismatchFunc func(ctx context.Context, tx chainsync.Tx, err error) error
+
+// WithTxVerification recomputes the id of each transaction that carries its
+// raw bytes and compares it with the id reported by ogmios.  Failures are
+// passed to fn; if fn is nil, the first failure stops the ChainSync
+func WithTxVerification(fn TxMismatchFunc) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.verifyTxIDs = true
+		opts.onTxMismatch = fn
+	}
+}
+
 // ChainSync replays the blockchain by invoking the callback for each block
 // By default, ChainSync stores no checkpoints and always restarts from origin.  These can
 // be overridden via WithPoints and WithStore
 func (c *Client) ChainSync(ctx context.Context, callback ChainSyncFunc, opts ...ChainSyncOption) (*ChainSync, error) {
 	options := buildChainSyncOptions(opts...)
+	if lease, ok := options.lease.(TTLLease); ok && options.leaseInterval >= lease.TTL() {
+		return nil, fmt.Errorf("failed to start chainsync: lease interval, %v, must be shorter than the lease ttl, %v", options.leaseInterval, lease.TTL())
+	}
 
 	done := make(chan struct{})
 	errs := make(chan error, 1)
@@ -123,13 +163,29 @@ func (c *Client) ChainSync(ctx context.Context, callback ChainSyncFunc, opts ...
 
 	go func() {
 		defer close(done)
+		if options.lease != nil {
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), options.leaseInterval)
+				defer cancel()
+				if err := options.lease.Release(ctx); err != nil {
+					c.options.logger.Info("failed to release lease", KV("err", err.Error()))
+				}
+			}()
+		}
 
 		var (
 			timeout = 10 * time.Second
+			checked bool // network verified; not repeated on reconnect
 			err     error
 		)
 		for {
-			err = c.doChainSync(ctx, callback, options)
+			if !checked {
+				err = c.CheckNetwork(ctx)
+				checked = err == nil
+			}
+			if checked {
+				err = c.doChainSync(ctx, callback, options)
+			}
 			if err != nil && isTemporaryError(err) {
 				if options.reconnect {
 					c.options.logger.Info("websocket connection error: will retry",
@@ -160,6 +216,15 @@ func (c *Client) ChainSync(ctx context.Context, callback ChainSyncFunc, opts ...
 }
 
 func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, options ChainSyncOptions) error {
+	if options.lease != nil {
+		if err := options.lease.Acquire(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to acquire lease: %w", err)
+		}
+	}
+
 	conn, _, err := websocket.DefaultDialer.Dial(c.options.endpoint, nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to ogmios, %v: %w", c.options.endpoint, err)
@@ -178,6 +243,17 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 		return nil
 	})
 
+	var leaseLost int64 // set once renewal fails; checkpoints must no longer be written
+	if options.lease != nil {
+		group.Go(func() error {
+			err := renewLease(ctx, options.lease, options.leaseInterval)
+			if err != nil {
+				atomic.StoreInt64(&leaseLost, 1)
+			}
+			return err
+		})
+	}
+
 	var connState int64 // 0 - open, 1 - closing, 2 - closed
 	group.Go(func() error {
 		<-ctx.Done()
@@ -225,6 +301,25 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 	group.Go(func() error {
 		checkSlot := options.minSlot > 0
 		last := newCircular(3)
+
+		// saveCheckpoint saves the final point and flushes batching stores
+		saveCheckpoint := func() error {
+			if atomic.LoadInt64(&leaseLost) > 0 {
+				return nil // another replica may own the checkpoint now
+			}
+			if point, ok := getPoint(last.list()...); ok {
+				if err := options.store.Save(context.Background(), point); err != nil {
+					return fmt.Errorf("chainsync client failed: %w", err)
+				}
+			}
+			if store, ok := options.store.(FlushStore); ok {
+				if err := store.Flush(context.Background()); err != nil {
+					return fmt.Errorf("chainsync client failed: %w", err)
+				}
+			}
+			return nil
+		}
+
 		for n := uint64(1); ; n++ {
 			messageType, data, err := conn.ReadMessage()
 			if err != nil {
@@ -234,7 +329,7 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 				var oe *net.OpError
 				if ok := errors.As(err, &oe); ok {
 					if v := atomic.LoadInt64(&connState); v > 0 {
-						return nil // connection closed
+						return saveCheckpoint() // connection closed
 					}
 				}
 				return fmt.Errorf("failed to read message from ogmios: %w", err)
@@ -242,12 +337,7 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 
 			select {
 			case <-ctx.Done():
-				if point, ok := getPoint(last.list()...); ok {
-					if err := options.store.Save(context.Background(), point); err != nil {
-						return fmt.Errorf("chainsync client failed: %w", err)
-					}
-				}
-				return nil
+				return saveCheckpoint()
 			case ch <- struct{}{}:
 				// request the next message
 			default:
@@ -260,12 +350,7 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 				continue
 
 			case websocket.CloseMessage:
-				if point, ok := getPoint(last.list()...); ok {
-					if err := options.store.Save(context.Background(), point); err != nil {
-						return fmt.Errorf("chainsync client failed: %w", err)
-					}
-				}
-				return nil
+				return saveCheckpoint()
 
 			case websocket.PingMessage:
 				if err := conn.WriteMessage(websocket.PongMessage, nil); err != nil {
@@ -292,10 +377,25 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 				}
 			}
 
+			if options.verifyTxIDs {
+				if err := verifyTxIDs(ctx, data, options.onTxMismatch); err != nil {
+					return fmt.Errorf("chainsync stopped: %w", err)
+				}
+			}
+
 			if err := callback(ctx, data); err != nil {
 				return fmt.Errorf("chainsync stopped: callback failed: %w", err)
 			}
 
+			// discard points that are no longer on chain
+			if store, ok := options.store.(TruncateStore); ok {
+				if point, ok := getRollBackward(data); ok {
+					if err := store.Truncate(ctx, point); err != nil {
+						return fmt.Errorf("chainsync client failed: %w", err)
+					}
+				}
+			}
+
 			// periodically save points to the store to allow graceful recovery
 			if n%c.options.saveInterval == 0 {
 				if point, ok := getPoint(last.prefix(data)...); ok {
@@ -356,6 +456,55 @@ func getPoint(data ...[]byte) (chainsync.Point, bool) {
 	return chainsync.Point{}, false
 }
 
+// verifyTxIDs checks the ids of the transactions in the json encoded
+// chainsync.Response if it is a RollForward
+func verifyTxIDs(ctx context.Context, data []byte, fn TxMismatchFunc) error {
+	var response chainsync.Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil // not a chainsync response
+	}
+	if response.Result == nil || response.Result.RollForward == nil {
+		return nil
+	}
+
+	block := response.Result.RollForward.Block.Block()
+	if block == nil {
+		return nil
+	}
+	for _, tx := range block.Body {
+		if tx.Raw == "" {
+			continue
+		}
+		if err := tx.VerifyID(); err != nil {
+			if fn == nil {
+				return fmt.Errorf("failed to verify tx, %v: %w", tx.ID, err)
+			}
+			if err := fn(ctx, tx, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getRollBackward returns the point rolled back to if the json encoded
+// chainsync.Response is a RollBackward
+func getRollBackward(data []byte) (chainsync.Point, bool) {
+	value, dataType, _, err := jsonparser.Get(data, "result", "RollBackward", "point")
+	if err != nil {
+		return chainsync.Point{}, false
+	}
+	if dataType == jsonparser.String {
+		return chainsync.PointString(value).Point(), true
+	}
+
+	var point chainsync.Point
+	if err := json.Unmarshal(value, &point); err != nil {
+		return chainsync.Point{}, false
+	}
+	return point, true
+}
+
 // isTemporaryError returns true if the error is recoverable
 func isTemporaryError(err error) bool {
 	wce := &websocket.CloseError{}
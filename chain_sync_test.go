@@ -17,10 +17,14 @@ package ogmigo
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -83,6 +87,53 @@ func TestClient_ChainSync(t *testing.T) {
 	}
 }
 
+type flushStore struct {
+	memStore
+	flushes int64
+}
+
+func (f *flushStore) Flush(context.Context) error {
+	atomic.AddInt64(&f.flushes, 1)
+	return nil
+}
+
+func TestClient_ChainSyncFlush(t *testing.T) {
+	server := httptest.NewServer(respond(`{"type":"jsonwsp/response","result":{"RollForward":{"block":{"babbage":{"headerHash":"ab","header":{"slot":10,"blockHeight":3}}},"tip":"origin"}}}`))
+	defer server.Close()
+
+	var (
+		ctx      = context.Background()
+		store    = &flushStore{}
+		received = make(chan struct{}, 1)
+		client   = New(WithEndpoint("ws" + strings.TrimPrefix(server.URL, "http")))
+	)
+
+	callback := func(context.Context, []byte) error {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		return nil
+	}
+	closer, err := client.ChainSync(ctx, callback, WithStore(store))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	<-received
+	if err := closer.Close(); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	<-closer.Done()
+
+	if got, want := atomic.LoadInt64(&store.flushes), int64(1); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got := store.saves; len(got) == 0 || got[len(got)-1].Slot() != 10 {
+		t.Fatalf("got %v; want final checkpoint at slot 10", got)
+	}
+}
+
 type echoStore struct {
 }
 
@@ -148,3 +199,83 @@ func Test_getInit(t *testing.T) {
 		}
 	})
 }
+
+func TestGetRollBackward(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		data := []byte(`{"type":"jsonwsp/response","result":{"RollBackward":{"point":{"slot":123,"hash":"abc"},"tip":{"slot":456,"hash":"def","blockNo":7}}}}`)
+		point, ok := getRollBackward(data)
+		if !ok {
+			t.Fatalf("got false; want true")
+		}
+		if got, want := point.String(), "slot=123 hash=abc"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		data := []byte(`{"type":"jsonwsp/response","result":{"RollBackward":{"point":"origin","tip":"origin"}}}`)
+		point, ok := getRollBackward(data)
+		if !ok {
+			t.Fatalf("got false; want true")
+		}
+		if got, want := point, chainsync.Origin; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("roll forward", func(t *testing.T) {
+		data := []byte(`{"type":"jsonwsp/response","result":{"RollForward":{"block":{},"tip":"origin"}}}`)
+		if _, ok := getRollBackward(data); ok {
+			t.Fatalf("got true; want false")
+		}
+	})
+}
+
+func TestVerifyTxIDs(t *testing.T) {
+	// [{0: [], 1: [], 2: 0}, {}, true, null]
+	raw := []byte{0x84, 0xa3, 0x00, 0x80, 0x01, 0x80, 0x02, 0x00, 0xa0, 0xf5, 0xf6}
+	id, err := chainsync.ComputeTxID(raw)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	response := func(id string) []byte {
+		return []byte(fmt.Sprintf(`{"type":"jsonwsp/response","result":{"RollForward":{"block":{"babbage":{"body":[{"id":%q,"raw":%q}]}},"tip":"origin"}}}`,
+			id, base64.StdEncoding.EncodeToString(raw)))
+	}
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		if err := verifyTxIDs(ctx, response(id), nil); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		err := verifyTxIDs(ctx, response("abc"), nil)
+		if !errors.Is(err, chainsync.ErrHashMismatch) {
+			t.Fatalf("got %v; want %v", err, chainsync.ErrHashMismatch)
+		}
+	})
+
+	t.Run("callback", func(t *testing.T) {
+		var got []string
+		fn := func(ctx context.Context, tx chainsync.Tx, err error) error {
+			got = append(got, tx.ID)
+			return nil
+		}
+		if err := verifyTxIDs(ctx, response("abc"), fn); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if len(got) != 1 || got[0] != "abc" {
+			t.Fatalf("got %v; want [abc]", got)
+		}
+	})
+
+	t.Run("roll backward", func(t *testing.T) {
+		data := []byte(`{"type":"jsonwsp/response","result":{"RollBackward":{"point":"origin","tip":"origin"}}}`)
+		if err := verifyTxIDs(ctx, data, nil); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+}
@@ -21,8 +21,6 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"regexp"
-	"strconv"
 	"sync/atomic"
 
 	"github.com/SundaeSwap-finance/ogmigo"
@@ -50,7 +48,7 @@ func main() {
 		&cli.StringSliceFlag{
 			Name:        "point",
 			Aliases:     []string{"p"},
-			Usage:       "initial starting point in the form {slot}/{hash} e.g.",
+			Usage:       "initial starting point in the form {slot}/{hash} or origin",
 			EnvVars:     []string{"POINT"},
 			Destination: &opts.Points,
 		},
@@ -77,20 +75,15 @@ func action(_ *cli.Context) error {
 
 	var (
 		ctx    = context.Background()
-		re     = regexp.MustCompile(`^(\d+)/([a-zA-Z0-9]+)$`)
 		points chainsync.Points
 	)
 
 	for _, s := range opts.Points.Value() {
-		match := re.FindStringSubmatch(s)
-		if len(match) != 3 {
-			return fmt.Errorf("ogmigo: failed to parse point, %v", s)
+		point, err := chainsync.ParsePoint(s)
+		if err != nil {
+			return fmt.Errorf("ogmigo: %w", err)
 		}
-		slot, _ := strconv.ParseUint(match[1], 10, 64)
-		points = append(points, chainsync.PointStruct{
-			Hash: match[2],
-			Slot: slot,
-		}.Point())
+		points = append(points, point)
 	}
 
 	var counter int64
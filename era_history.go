@@ -0,0 +1,161 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ErrPastHorizon is returned, wrapped, when a slot, time or epoch lies beyond
+// the era history and so cannot be converted safely
+var ErrPastHorizon = errors.New("past forecast horizon")
+
+// picoseconds per second; EraBound.Time is relative to the system start in
+// picoseconds while EraParameters.SlotLength is in seconds
+var picosPerSecond = big.NewInt(1e12)
+
+// bounded returns true if the era has a known end; the final era may not
+func (e EraSummary) bounded() bool {
+	return e.End.Slot > e.Start.Slot
+}
+
+func (e EraSummary) slotLength() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(e.Parameters.SlotLength), picosPerSecond)
+}
+
+// summaryBySlot returns the era containing the slot
+func (h EraHistory) summaryBySlot(slot uint64) (EraSummary, error) {
+	for _, s := range h.Summaries {
+		if slot >= s.Start.Slot && (slot < s.End.Slot || !s.bounded()) {
+			return s, nil
+		}
+	}
+	return EraSummary{}, fmt.Errorf("slot %v: %w", slot, ErrPastHorizon)
+}
+
+// SlotToRelativeTime returns the start of the slot in picoseconds since the
+// system start
+func (h EraHistory) SlotToRelativeTime(slot uint64) (*big.Int, error) {
+	s, err := h.summaryBySlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	if s.Parameters.SlotLength == 0 {
+		return nil, fmt.Errorf("invalid era summary starting at slot %v: zero slot length", s.Start.Slot)
+	}
+
+	offset := new(big.Int).SetUint64(slot - s.Start.Slot)
+	offset.Mul(offset, s.slotLength())
+	return offset.Add(offset, &s.Start.Time), nil
+}
+
+// SlotToTime returns the wall clock start of the slot for a network that
+// started at systemStart
+func (h EraHistory) SlotToTime(systemStart time.Time, slot uint64) (time.Time, error) {
+	relative, err := h.SlotToRelativeTime(slot)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	nanos := new(big.Int).Quo(relative, big.NewInt(1000))
+	if !nanos.IsInt64() {
+		return time.Time{}, fmt.Errorf("slot %v overflows time.Duration", slot)
+	}
+	return systemStart.Add(time.Duration(nanos.Int64())), nil
+}
+
+// TimeToSlot returns the slot containing t for a network that started at
+// systemStart
+func (h EraHistory) TimeToSlot(systemStart time.Time, t time.Time) (uint64, error) {
+	if t.Before(systemStart) {
+		return 0, fmt.Errorf("time %v precedes system start %v", t, systemStart)
+	}
+
+	relative := big.NewInt(int64(t.Sub(systemStart)))
+	relative.Mul(relative, big.NewInt(1000))
+
+	for _, s := range h.Summaries {
+		if relative.Cmp(&s.Start.Time) < 0 || (s.bounded() && relative.Cmp(&s.End.Time) >= 0) {
+			continue
+		}
+		if s.Parameters.SlotLength == 0 {
+			return 0, fmt.Errorf("invalid era summary starting at slot %v: zero slot length", s.Start.Slot)
+		}
+
+		offset := new(big.Int).Sub(relative, &s.Start.Time)
+		offset.Quo(offset, s.slotLength())
+		if !offset.IsUint64() {
+			return 0, fmt.Errorf("time %v overflows slot", t)
+		}
+		return s.Start.Slot + offset.Uint64(), nil
+	}
+	return 0, fmt.Errorf("time %v: %w", t, ErrPastHorizon)
+}
+
+// SlotToEpoch returns the epoch containing the slot
+func (h EraHistory) SlotToEpoch(slot uint64) (uint64, error) {
+	s, err := h.summaryBySlot(slot)
+	if err != nil {
+		return 0, err
+	}
+	if s.Parameters.EpochLength == 0 {
+		return 0, fmt.Errorf("invalid era summary starting at slot %v: zero epoch length", s.Start.Slot)
+	}
+	return s.Start.Epoch + (slot-s.Start.Slot)/s.Parameters.EpochLength, nil
+}
+
+// EpochFirstSlot returns the first slot of the epoch
+func (h EraHistory) EpochFirstSlot(epoch uint64) (uint64, error) {
+	for _, s := range h.Summaries {
+		if epoch >= s.Start.Epoch && (epoch < s.End.Epoch || !s.bounded()) {
+			return s.Start.Slot + (epoch-s.Start.Epoch)*s.Parameters.EpochLength, nil
+		}
+	}
+	return 0, fmt.Errorf("epoch %v: %w", epoch, ErrPastHorizon)
+}
+
+// Horizon returns the first slot that cannot be converted safely given the
+// current tip: the end of the final era if known, otherwise the tip plus the
+// safe zone of the final era
+func (h EraHistory) Horizon(tip uint64) (uint64, error) {
+	if len(h.Summaries) == 0 {
+		return 0, fmt.Errorf("empty era history")
+	}
+
+	last := h.Summaries[len(h.Summaries)-1]
+	if last.bounded() {
+		return last.End.Slot, nil
+	}
+	if tip < last.Start.Slot {
+		tip = last.Start.Slot
+	}
+	return tip + last.Parameters.SafeZone, nil
+}
+
+// CheckHorizon returns an error wrapping ErrPastHorizon if the slot lies at or
+// beyond the horizon for the current tip
+func (h EraHistory) CheckHorizon(tip, slot uint64) error {
+	horizon, err := h.Horizon(tip)
+	if err != nil {
+		return err
+	}
+	if slot >= horizon {
+		return fmt.Errorf("slot %v is at or beyond horizon %v: %w", slot, horizon, ErrPastHorizon)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mainnet byron and shelley eras followed by an open ended era
+const testEraSummaries = `[
+  {"start":{"time":0,"slot":0,"epoch":0},"end":{"time":89856000000000000000,"slot":4492800,"epoch":208},"parameters":{"epochLength":21600,"slotLength":20,"safeZone":4320}},
+  {"start":{"time":89856000000000000000,"slot":4492800,"epoch":208},"end":{"time":101952000000000000000,"slot":16588800,"epoch":236},"parameters":{"epochLength":432000,"slotLength":1,"safeZone":129600}},
+  {"start":{"time":101952000000000000000,"slot":16588800,"epoch":236},"end":null,"parameters":{"epochLength":432000,"slotLength":1,"safeZone":129600}}
+]`
+
+func testEraHistory(t *testing.T) EraHistory {
+	var summaries []EraSummary
+	if err := json.Unmarshal([]byte(testEraSummaries), &summaries); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return EraHistory{Summaries: summaries}
+}
+
+func TestEraHistory_SlotToTime(t *testing.T) {
+	var (
+		history     = testEraHistory(t)
+		systemStart = time.Date(2017, 9, 23, 21, 44, 51, 0, time.UTC)
+	)
+
+	testCases := map[string]struct {
+		slot uint64
+		want time.Time
+	}{
+		"origin": {
+			slot: 0,
+			want: systemStart,
+		},
+		"byron": {
+			slot: 1,
+			want: systemStart.Add(20 * time.Second),
+		},
+		"shelley hard fork": {
+			slot: 4492800,
+			want: time.Date(2020, 7, 29, 21, 44, 51, 0, time.UTC),
+		},
+		"open ended era": {
+			slot: 16588800 + 100,
+			want: systemStart.Add(101952100 * time.Second),
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, err := history.SlotToTime(systemStart, tc.slot)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.True(t, tc.want.Equal(got), "got %v; want %v", got, tc.want)
+
+			slot, err := history.TimeToSlot(systemStart, got)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, tc.slot, slot)
+		})
+	}
+
+	t.Run("within byron slot", func(t *testing.T) {
+		slot, err := history.TimeToSlot(systemStart, systemStart.Add(39*time.Second))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.EqualValues(t, 1, slot)
+	})
+
+	t.Run("before system start", func(t *testing.T) {
+		_, err := history.TimeToSlot(systemStart, systemStart.Add(-time.Second))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestEraHistory_Epochs(t *testing.T) {
+	history := testEraHistory(t)
+
+	for slot, want := range map[uint64]uint64{
+		0:        0,
+		21599:    0,
+		21600:    1,
+		4492800:  208,
+		16588799: 235,
+		16588800: 236,
+		17020800: 237,
+	} {
+		got, err := history.SlotToEpoch(slot)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, want, got, "slot %v", slot)
+	}
+
+	for epoch, want := range map[uint64]uint64{
+		0:   0,
+		1:   21600,
+		208: 4492800,
+		209: 4924800,
+		237: 17020800,
+	} {
+		got, err := history.EpochFirstSlot(epoch)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, want, got, "epoch %v", epoch)
+	}
+}
+
+func TestEraHistory_Horizon(t *testing.T) {
+	history := testEraHistory(t)
+
+	horizon, err := history.Horizon(20000000)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 20129600, horizon)
+	assert.Nil(t, history.CheckHorizon(20000000, 20129599))
+	if err := history.CheckHorizon(20000000, 20129600); !errors.Is(err, ErrPastHorizon) {
+		t.Fatalf("got %v; want %v", err, ErrPastHorizon)
+	}
+
+	bounded := EraHistory{Summaries: history.Summaries[:2]}
+	horizon, err = bounded.Horizon(5000000)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 16588800, horizon)
+
+	if _, err := bounded.SlotToTime(time.Now(), 16588800); !errors.Is(err, ErrPastHorizon) {
+		t.Fatalf("got %v; want %v", err, ErrPastHorizon)
+	}
+	if _, err := bounded.EpochFirstSlot(236); !errors.Is(err, ErrPastHorizon) {
+		t.Fatalf("got %v; want %v", err, ErrPastHorizon)
+	}
+
+	_, err = EraHistory{}.Horizon(0)
+	assert.NotNil(t, err)
+}
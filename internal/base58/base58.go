@@ -0,0 +1,87 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package base58 implements the bitcoin alphabet base58 encoding used by
+// byron addresses
+package base58
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	radix   = big.NewInt(58)
+	indexes [256]int
+)
+
+func init() {
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		indexes[alphabet[i]] = i
+	}
+}
+
+// Decode returns the bytes encoded by the base58 string s
+func Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, fmt.Errorf("base58: empty string")
+	}
+
+	var zeros int
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		v := indexes[s[i]]
+		if v < 0 {
+			return nil, fmt.Errorf("base58: invalid character, %q", s[i])
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// Encode returns the base58 encoding of data
+func Encode(data []byte) string {
+	var zeros int
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	var (
+		n   = new(big.Int).SetBytes(data)
+		mod = new(big.Int)
+		b   []byte
+	)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		b = append(b, alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		b = append(b, alphabet[0])
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package base58
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"1":               "00",
+		"11":              "0000",
+		"2g":              "61",
+		"a3gV":            "626262",
+		"1112":            "00000001",
+		"StV1DL6CwTryKyV": "68656c6c6f20776f726c64",
+	}
+
+	for s, want := range tests {
+		if s == "" {
+			if _, err := Decode(s); err == nil {
+				t.Fatalf("got nil; want err")
+			}
+			continue
+		}
+
+		data, err := Decode(s)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got := hex.EncodeToString(data); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got := Encode(data); got != s {
+			t.Fatalf("got %v; want %v", got, s)
+		}
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, s := range []string{"0", "O", "I", "l", "abc+"} {
+		if _, err := Decode(s); err == nil {
+			t.Fatalf("got nil; want err for %v", s)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bech32 implements BIP-173 bech32 encoding without the 90 character
+// limit, as Cardano addresses routinely exceed it
+package bech32
+
+import (
+	"fmt"
+	"strings"
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	var data []byte
+	for i := 0; i < len(hrp); i++ {
+		data = append(data, hrp[i]>>5)
+	}
+	data = append(data, 0)
+	for i := 0; i < len(hrp); i++ {
+		data = append(data, hrp[i]&31)
+	}
+	return data
+}
+
+// convertBits regroups data from frombits to tobits per element
+func convertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var (
+		acc    uint32
+		bits   uint
+		result []byte
+		maxv   = uint32(1)<<tobits - 1
+	)
+	for _, v := range data {
+		if uint32(v)>>frombits != 0 {
+			return nil, fmt.Errorf("invalid data range: %v", v)
+		}
+		acc = acc<<frombits | uint32(v)
+		bits += frombits
+		for bits >= tobits {
+			bits -= tobits
+			result = append(result, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(tobits-bits)&maxv))
+		}
+	} else if bits >= frombits || acc<<(tobits-bits)&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return result, nil
+}
+
+// Decode returns the human readable part and the data bytes of a bech32 string
+func Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("bech32: mixed case string, %v", s)
+	}
+	s = strings.ToLower(s)
+
+	index := strings.LastIndexByte(s, '1')
+	if index < 1 || index+7 > len(s) {
+		return "", nil, fmt.Errorf("bech32: invalid separator position, %v", s)
+	}
+
+	hrp := s[:index]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("bech32: invalid character in human readable part, %v", s)
+		}
+	}
+
+	var values []byte
+	for _, c := range s[index+1:] {
+		v := strings.IndexRune(charset, c)
+		if v < 0 {
+			return "", nil, fmt.Errorf("bech32: invalid character, %q", c)
+		}
+		values = append(values, byte(v))
+	}
+
+	if polymod(append(hrpExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("bech32: invalid checksum, %v", s)
+	}
+
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("bech32: %w", err)
+	}
+	return hrp, data, nil
+}
+
+// Encode returns the bech32 encoding of data with the provided human readable part
+func Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("bech32: %w", err)
+	}
+
+	hrp = strings.ToLower(hrp)
+	mod := polymod(append(append(hrpExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+	for i := 0; i < 6; i++ {
+		values = append(values, byte(mod>>uint(5*(5-i))&31))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(charset[v])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bech32
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	const s = "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"
+
+	hrp, data, err := Decode(s)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := hrp, "stake"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := hex.EncodeToString(data), "e1337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	encoded, err := Encode(hrp, data)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := encoded, s; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"stake1",
+		"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgx", // checksum
+		"Stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw", // mixed case
+		"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgb", // invalid character
+	} {
+		if _, _, err := Decode(s); err == nil {
+			t.Fatalf("got nil; want err for %v", s)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cbor reads and writes the low level cbor structures shared by the
+// transaction, plutus data and fee code.  Unlike a generic decoder, Reader
+// keeps map entries in order and exposes the raw bytes of each item, both of
+// which are needed to decode ledger structures faithfully
+package cbor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+)
+
+const (
+	MajorUint   = 0
+	MajorNegInt = 1
+	MajorBytes  = 2
+	MajorText   = 3
+	MajorArray  = 4
+	MajorMap    = 5
+	MajorTag    = 6
+	MajorSimple = 7
+
+	Indefinite = 31
+	Break      = 0xff
+	False      = 0xf4
+	True       = 0xf5
+	Null       = 0xf6
+
+	TagPositiveBignum = 2
+	TagNegativeBignum = 3
+	TagEncodedCBOR    = 24
+	TagRational       = 30
+	TagSet            = 258
+)
+
+// Reader reads cbor items in order
+type Reader struct {
+	data []byte
+	pos  int
+}
+
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Done returns true once every byte has been read
+func (r *Reader) Done() bool {
+	return r.pos >= len(r.data)
+}
+
+// Remaining returns the number of unread bytes
+func (r *Reader) Remaining() int {
+	return len(r.data) - r.pos
+}
+
+// Peek returns the next byte without consuming it
+func (r *Reader) Peek() (byte, error) {
+	if r.Done() {
+		return 0, fmt.Errorf("unexpected end of cbor at offset %v", r.pos)
+	}
+	return r.data[r.pos], nil
+}
+
+// PeekMajor returns the major type of the next item without consuming it
+func (r *Reader) PeekMajor() (byte, error) {
+	b, err := r.Peek()
+	return b >> 5, err
+}
+
+// Head reads an item head; indefinite is set for indefinite length items
+func (r *Reader) Head() (major byte, arg uint64, indefinite bool, err error) {
+	b, err := r.Peek()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	r.pos++
+
+	major, info := b>>5, b&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info == Indefinite:
+		return major, 0, true, nil
+	case info > 27:
+		return 0, 0, false, fmt.Errorf("invalid cbor additional info, %v, at offset %v", info, r.pos-1)
+	}
+
+	size := 1 << (info - 24)
+	if r.pos+size > len(r.data) {
+		return 0, 0, false, fmt.Errorf("unexpected end of cbor at offset %v", r.pos)
+	}
+	v := r.data[r.pos : r.pos+size]
+	r.pos += size
+
+	switch size {
+	case 1:
+		arg = uint64(v[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(v))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(v))
+	default:
+		arg = binary.BigEndian.Uint64(v)
+	}
+	return major, arg, false, nil
+}
+
+// Expect reads an item head of the given major type
+func (r *Reader) Expect(want byte) (uint64, bool, error) {
+	start := r.pos
+	major, arg, indefinite, err := r.Head()
+	if err != nil {
+		return 0, false, err
+	}
+	if major != want {
+		return 0, false, fmt.Errorf("got cbor major type %v; want %v at offset %v", major, want, start)
+	}
+	return arg, indefinite, nil
+}
+
+func (r *Reader) Uint() (uint64, error) {
+	v, indefinite, err := r.Expect(MajorUint)
+	if err == nil && indefinite {
+		err = fmt.Errorf("invalid indefinite length integer at offset %v", r.pos-1)
+	}
+	return v, err
+}
+
+// Int reads an integer of any size, including bignums
+func (r *Reader) Int() (*big.Int, error) {
+	start := r.pos
+	major, arg, _, err := r.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case MajorUint:
+		return new(big.Int).SetUint64(arg), nil
+	case MajorNegInt:
+		v := new(big.Int).SetUint64(arg)
+		return v.Neg(v).Sub(v, big.NewInt(1)), nil
+	case MajorTag:
+		if arg == TagPositiveBignum || arg == TagNegativeBignum {
+			return r.Bignum(arg)
+		}
+	}
+	return nil, fmt.Errorf("expected cbor integer at offset %v", start)
+}
+
+// Bignum reads the byte string that follows a bignum tag
+func (r *Reader) Bignum(tag uint64) (*big.Int, error) {
+	data, err := r.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	v := new(big.Int).SetBytes(data)
+	if tag == TagNegativeBignum {
+		v.Neg(v).Sub(v, big.NewInt(1))
+	}
+	return v, nil
+}
+
+func (r *Reader) Bytes() ([]byte, error) {
+	return r.chunks(MajorBytes)
+}
+
+func (r *Reader) Text() (string, error) {
+	data, err := r.chunks(MajorText)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("invalid utf8 text string")
+	}
+	return string(data), nil
+}
+
+func (r *Reader) chunks(major byte) ([]byte, error) {
+	n, indefinite, err := r.Expect(major)
+	if err != nil {
+		return nil, err
+	}
+	if !indefinite {
+		if uint64(len(r.data)-r.pos) < n {
+			return nil, fmt.Errorf("unexpected end of cbor at offset %v", r.pos)
+		}
+		v := r.data[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return append([]byte{}, v...), nil
+	}
+
+	data := []byte{}
+	for {
+		if b, err := r.Peek(); err != nil {
+			return nil, err
+		} else if b == Break {
+			r.pos++
+			return data, nil
+		}
+		chunk, err := r.chunks(major)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+	}
+}
+
+func (r *Reader) Bool() (bool, error) {
+	b, err := r.Peek()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case False:
+		r.pos++
+		return false, nil
+	case True:
+		r.pos++
+		return true, nil
+	default:
+		return false, fmt.Errorf("expected cbor bool at offset %v", r.pos)
+	}
+}
+
+// Null consumes a null and returns true if one is next
+func (r *Reader) Null() bool {
+	if b, err := r.Peek(); err == nil && b == Null {
+		r.pos++
+		return true
+	}
+	return false
+}
+
+func (r *Reader) Tag() (uint64, error) {
+	v, _, err := r.Expect(MajorTag)
+	return v, err
+}
+
+// SkipTag consumes the tag if it is next
+func (r *Reader) SkipTag(tag uint64) {
+	start := r.pos
+	if major, arg, _, err := r.Head(); err != nil || major != MajorTag || arg != tag {
+		r.pos = start
+	}
+}
+
+// Array calls fn once per element; an optional set tag is ignored
+func (r *Reader) Array(fn func(i int) error) error {
+	r.SkipTag(TagSet)
+	n, indefinite, err := r.Expect(MajorArray)
+	if err != nil {
+		return err
+	}
+	return r.Items(n, indefinite, fn)
+}
+
+// Map calls fn once per entry; fn reads both the key and the value
+func (r *Reader) Map(fn func(i int) error) error {
+	n, indefinite, err := r.Expect(MajorMap)
+	if err != nil {
+		return err
+	}
+	return r.Items(n, indefinite, fn)
+}
+
+// Items calls fn n times or, if indefinite, until a break is read
+func (r *Reader) Items(n uint64, indefinite bool, fn func(i int) error) error {
+	for i := 0; indefinite || uint64(i) < n; i++ {
+		if indefinite {
+			b, err := r.Peek()
+			if err != nil {
+				return err
+			}
+			if b == Break {
+				r.pos++
+				return nil
+			}
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Raw skips the next item and returns its encoded bytes
+func (r *Reader) Raw() ([]byte, error) {
+	start := r.pos
+	if err := r.Skip(); err != nil {
+		return nil, err
+	}
+	return r.data[start:r.pos], nil
+}
+
+// Skip consumes the next item
+func (r *Reader) Skip() error {
+	start := r.pos
+	major, arg, indefinite, err := r.Head()
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case MajorUint, MajorNegInt:
+		return nil
+	case MajorBytes, MajorText:
+		r.pos = start
+		_, err := r.chunks(major)
+		return err
+	case MajorArray:
+		return r.Items(arg, indefinite, func(int) error { return r.Skip() })
+	case MajorMap:
+		return r.Items(arg, indefinite, func(int) error {
+			if err := r.Skip(); err != nil {
+				return err
+			}
+			return r.Skip()
+		})
+	case MajorTag:
+		return r.Skip()
+	default:
+		if indefinite {
+			return fmt.Errorf("unexpected cbor break at offset %v", start)
+		}
+		return nil
+	}
+}
+
+// AppendHead appends the head of an item with the given major type and
+// argument using the shortest encoding
+func AppendHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major<<5|byte(n))
+	case n <= 0xff:
+		return append(b, major<<5|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(b, major<<5|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(b, major<<5|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major<<5|27), n)
+	}
+}
+
+// HeadSize returns the size of the shortest head encoding argument n
+func HeadSize(n uint64) uint64 {
+	switch {
+	case n < 24:
+		return 1
+	case n <= 0xff:
+		return 2
+	case n <= 0xffff:
+		return 3
+	case n <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return data
+}
+
+func TestReader(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		for hexString, want := range map[string]string{
+			"00":                     "0",
+			"17":                     "23",
+			"1903e8":                 "1000",
+			"20":                     "-1",
+			"3903e7":                 "-1000",
+			"1bffffffffffffffff":     "18446744073709551615",
+			"c249010000000000000000": "18446744073709551616",
+			"c349010000000000000000": "-18446744073709551617",
+		} {
+			got, err := NewReader(mustHex(t, hexString)).Int()
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, want, got.String(), hexString)
+		}
+	})
+
+	t.Run("indefinite", func(t *testing.T) {
+		// indefinite byte string and indefinite array
+		r := NewReader(mustHex(t, "5f42010243030405ff9f0102ff"))
+		data, err := r.Bytes()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, []byte{1, 2, 3, 4, 5}, data)
+
+		var items []uint64
+		err = r.Array(func(int) error {
+			v, err := r.Uint()
+			items = append(items, v)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, []uint64{1, 2}, items)
+		assert.True(t, r.Done())
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		// {1: [2, h'03'], "a": tag 24 h'f6'} followed by 7
+		item := mustHex(t, "a201820241036161d81841f6")
+		r := NewReader(append(item, 0x07))
+		got, err := r.Raw()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, item, got)
+
+		v, err := r.Uint()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.EqualValues(t, 7, v)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		err := NewReader(mustHex(t, "8301")).Skip()
+		assert.NotNil(t, err)
+
+		_, err = NewReader(mustHex(t, "4401")).Bytes()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		var items []uint64
+		r := NewReader(mustHex(t, "d90102820102"))
+		err := r.Array(func(int) error {
+			v, err := r.Uint()
+			items = append(items, v)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, []uint64{1, 2}, items)
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		_, err := NewReader(mustHex(t, "01")).Bool()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestAppendHead(t *testing.T) {
+	for n, want := range map[uint64]string{
+		0:          "40",
+		23:         "57",
+		24:         "5818",
+		1000:       "5903e8",
+		1000000:    "5a000f4240",
+		1 << 40:    "5b0000010000000000",
+		0xffffffff: "5affffffff",
+	} {
+		got := AppendHead(nil, MajorBytes, n)
+		assert.Equal(t, want, hex.EncodeToString(got))
+		assert.EqualValues(t, len(got), HeadSize(n))
+	}
+}
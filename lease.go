@@ -0,0 +1,67 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLeaseLost is returned when a lease could not be renewed because it is
+// no longer held by the caller
+var ErrLeaseLost = errors.New("lease lost")
+
+// Lease provides leader election so that only one replica runs ChainSync for
+// a given cursor while the others wait
+type Lease interface {
+	// Acquire blocks until the lease is held or the context is cancelled.
+	// Acquire must succeed immediately if the lease is already held by the caller
+	Acquire(ctx context.Context) error
+	// Renew extends the lease; implementations should return an error wrapping
+	// ErrLeaseLost if the lease is no longer held by the caller
+	Renew(ctx context.Context) error
+	// Release gives up the lease if it is held by the caller
+	Release(ctx context.Context) error
+}
+
+// TTLLease is implemented by leases that expire unless renewed within TTL.
+// ChainSync requires the WithLease interval to be shorter than the TTL
+type TTLLease interface {
+	Lease
+	// TTL returns how long the lease is held without renewal
+	TTL() time.Duration
+}
+
+// renewLease renews the lease every interval until the context is done
+func renewLease(ctx context.Context, lease Lease, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := lease.Renew(ctx); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("chainsync stopped: failed to renew lease: %w", err)
+			}
+		}
+	}
+}
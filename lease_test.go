@@ -0,0 +1,91 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mockLease struct {
+	renewals int64
+	limit    int64
+}
+
+func (m *mockLease) Acquire(context.Context) error { return nil }
+func (m *mockLease) Release(context.Context) error { return nil }
+func (m *mockLease) Renew(context.Context) error {
+	if v := atomic.AddInt64(&m.renewals, 1); v > m.limit {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+type ttlLease struct {
+	mockLease
+	ttl time.Duration
+}
+
+func (t *ttlLease) TTL() time.Duration { return t.ttl }
+
+func TestRenewLease(t *testing.T) {
+	t.Run("lost", func(t *testing.T) {
+		lease := &mockLease{limit: 2}
+		err := renewLease(context.Background(), lease, time.Millisecond)
+		if !errors.Is(err, ErrLeaseLost) {
+			t.Fatalf("got %v; want %v", err, ErrLeaseLost)
+		}
+		if got, want := atomic.LoadInt64(&lease.renewals), int64(3); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		lease := &mockLease{}
+		if err := renewLease(ctx, lease, time.Millisecond); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+}
+
+func TestWithLease(t *testing.T) {
+	lease := &mockLease{}
+	options := buildChainSyncOptions(WithLease(lease, 0))
+	if options.lease != lease {
+		t.Fatalf("got %v; want %v", options.lease, lease)
+	}
+	if got, want := options.leaseInterval, 5*time.Second; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestWithLease_TTL(t *testing.T) {
+	lease := &ttlLease{ttl: 3 * time.Second}
+	options := buildChainSyncOptions(WithLease(lease, 0))
+	if got, want := options.leaseInterval, time.Second; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	client := New()
+	if _, err := client.ChainSync(context.Background(), nil, WithLease(lease, 3*time.Second)); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/network"
+)
+
+// ErrNetworkMismatch is returned, wrapped, when ogmios is connected to a
+// network other than the one specified via WithNetwork
+var ErrNetworkMismatch = errors.New("network mismatch")
+
+// Network returns the network specified via WithNetwork, if any
+func (c *Client) Network() (network.Network, bool) {
+	if c.options.network == nil {
+		return network.Network{}, false
+	}
+	return *c.options.network, true
+}
+
+// CheckNetwork verifies the system start reported by ogmios matches the
+// network specified via WithNetwork.  It is a no-op if no network was
+// specified.  The network magic is not compared as ogmios only reports it via
+// the genesisConfig query, which fails while the node is still in byron; the
+// system start is available in every era and distinguishes the presets
+func (c *Client) CheckNetwork(ctx context.Context) error {
+	want, ok := c.Network()
+	if !ok {
+		return nil
+	}
+
+	got, err := c.SystemStart(ctx)
+	if err != nil {
+		return err
+	}
+	if !got.Equal(want.SystemStart) {
+		return fmt.Errorf("ogmios system start, %v, does not match %v system start, %v: %w", got, want.Name, want.SystemStart, ErrNetworkMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/network"
+)
+
+// respond replies to every request with the provided message
+func respond(message string) http.HandlerFunc {
+	var upgrader = websocket.Upgrader{}
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+			if err := c.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestClient_CheckNetwork(t *testing.T) {
+	server := httptest.NewServer(respond(`{"type":"jsonwsp/response","result":"2022-06-01T00:00:00Z"}`))
+	defer server.Close()
+
+	var (
+		ctx      = context.Background()
+		endpoint = WithEndpoint("ws" + strings.TrimPrefix(server.URL, "http"))
+	)
+
+	t.Run("match", func(t *testing.T) {
+		client := New(endpoint, WithNetwork(network.Preprod))
+		if err := client.CheckNetwork(ctx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, ok := client.Network(); !ok || got.Name != "preprod" {
+			t.Fatalf("got %v, %v; want preprod, true", got, ok)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		client := New(endpoint, WithNetwork(network.Mainnet))
+		if err := client.CheckNetwork(ctx); !errors.Is(err, ErrNetworkMismatch) {
+			t.Fatalf("got %v; want %v", err, ErrNetworkMismatch)
+		}
+	})
+
+	t.Run("chainsync", func(t *testing.T) {
+		client := New(endpoint, WithNetwork(network.Mainnet))
+		closer, err := client.ChainSync(ctx, nil, WithReconnect(true))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		<-closer.Done()
+		if err := closer.Close(); !errors.Is(err, ErrNetworkMismatch) {
+			t.Fatalf("got %v; want %v", err, ErrNetworkMismatch)
+		}
+	})
+
+	t.Run("unspecified", func(t *testing.T) {
+		client := New(WithEndpoint("ws://127.0.0.1:0"))
+		if err := client.CheckNetwork(ctx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+}
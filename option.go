@@ -14,10 +14,15 @@
 
 package ogmigo
 
+import (
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/network"
+)
+
 // Options available to ogmios client
 type Options struct {
 	endpoint     string
 	logger       Logger
+	network      *network.Network // expected network; nil to skip the check
 	pipeline     int
 	saveInterval uint64
 }
@@ -46,6 +51,14 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithNetwork specifies the network ogmios is expected to be connected to.
+// ChainSync verifies it once before syncing; see also Client.CheckNetwork
+func WithNetwork(n network.Network) Option {
+	return func(opts *Options) {
+		opts.network = &n
+	}
+}
+
 // WithPipeline allows number of pipelined ogmios requests to be provided
 func WithPipeline(n int) Option {
 	return func(opts *Options) {
@@ -0,0 +1,442 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package address parses shelley and byron cardano addresses and exposes the
+// credentials they carry
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/base58"
+	"github.com/SundaeSwap-finance/ogmigo/internal/bech32"
+)
+
+// Type identifies the address format from the upper nibble of the header byte
+type Type byte
+
+const (
+	BaseKeyKey       Type = 0x0
+	BaseScriptKey    Type = 0x1
+	BaseKeyScript    Type = 0x2
+	BaseScriptScript Type = 0x3
+	PointerKey       Type = 0x4
+	PointerScript    Type = 0x5
+	EnterpriseKey    Type = 0x6
+	EnterpriseScript Type = 0x7
+	Byron            Type = 0x8
+	RewardKey        Type = 0xe
+	RewardScript     Type = 0xf
+)
+
+const (
+	Testnet byte = 0
+	Mainnet byte = 1
+)
+
+const hashSize = 28
+
+func (t Type) String() string {
+	switch t {
+	case BaseKeyKey, BaseScriptKey, BaseKeyScript, BaseScriptScript:
+		return "base"
+	case PointerKey, PointerScript:
+		return "pointer"
+	case EnterpriseKey, EnterpriseScript:
+		return "enterprise"
+	case Byron:
+		return "byron"
+	case RewardKey, RewardScript:
+		return "reward"
+	default:
+		return fmt.Sprintf("unknown(%v)", byte(t))
+	}
+}
+
+// IsBase returns true for addresses with both a payment and stake credential
+func (t Type) IsBase() bool { return t <= BaseScriptScript }
+
+// IsPointer returns true for addresses that reference a stake certificate
+func (t Type) IsPointer() bool { return t == PointerKey || t == PointerScript }
+
+// IsEnterprise returns true for addresses without a stake part
+func (t Type) IsEnterprise() bool { return t == EnterpriseKey || t == EnterpriseScript }
+
+// IsReward returns true for stake (reward account) addresses
+func (t Type) IsReward() bool { return t == RewardKey || t == RewardScript }
+
+// Credential is a blake2b-224 hash of either a verification key or a script
+type Credential struct {
+	Hash   []byte
+	Script bool
+}
+
+// KeyCredential returns a credential for the hex encoded key hash
+func KeyCredential(hash string) (Credential, error) {
+	return newCredential(hash, false)
+}
+
+// ScriptCredential returns a credential for the hex encoded script hash
+func ScriptCredential(hash string) (Credential, error) {
+	return newCredential(hash, true)
+}
+
+func newCredential(hash string, script bool) (Credential, error) {
+	data, err := hex.DecodeString(hash)
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to decode credential hash, %v: %w", hash, err)
+	}
+	if len(data) != hashSize {
+		return Credential{}, fmt.Errorf("failed to decode credential hash, %v: got %v bytes; want %v", hash, len(data), hashSize)
+	}
+	return Credential{Hash: data, Script: script}, nil
+}
+
+// Hex returns the hex encoded credential hash
+func (c Credential) Hex() string {
+	return hex.EncodeToString(c.Hash)
+}
+
+func (c Credential) Equal(that Credential) bool {
+	return c.Script == that.Script && bytes.Equal(c.Hash, that.Hash)
+}
+
+// Pointer locates the stake registration certificate of a pointer address
+type Pointer struct {
+	Slot      uint64
+	TxIndex   uint64
+	CertIndex uint64
+}
+
+// Address is a parsed cardano address. Shelley addresses expose their
+// credentials directly; byron addresses retain their raw bytes and only
+// expose the network
+type Address struct {
+	Type    Type
+	Network byte
+	// Payment is set for base, pointer and enterprise addresses
+	Payment *Credential
+	// Stake is set for base and reward addresses
+	Stake *Credential
+	// Pointer is set for pointer addresses
+	Pointer *Pointer
+	// Byron is set for byron addresses
+	Byron *ByronAddress
+}
+
+// NewBase returns a base address holding the payment and stake credentials
+func NewBase(network byte, payment, stake Credential) Address {
+	t := BaseKeyKey
+	if payment.Script {
+		t |= 0x1
+	}
+	if stake.Script {
+		t |= 0x2
+	}
+	return Address{Type: t, Network: network, Payment: &payment, Stake: &stake}
+}
+
+// NewEnterprise returns an address with a payment credential and no stake part
+func NewEnterprise(network byte, payment Credential) Address {
+	t := EnterpriseKey
+	if payment.Script {
+		t = EnterpriseScript
+	}
+	return Address{Type: t, Network: network, Payment: &payment}
+}
+
+// NewPointer returns an address whose stake part refers to a certificate
+func NewPointer(network byte, payment Credential, pointer Pointer) Address {
+	t := PointerKey
+	if payment.Script {
+		t = PointerScript
+	}
+	return Address{Type: t, Network: network, Payment: &payment, Pointer: &pointer}
+}
+
+// NewReward returns the stake address of the stake credential
+func NewReward(network byte, stake Credential) Address {
+	t := RewardKey
+	if stake.Script {
+		t = RewardScript
+	}
+	return Address{Type: t, Network: network, Stake: &stake}
+}
+
+// Parse parses a bech32 shelley address or a base58 byron address.  The
+// bech32 prefix must match the network and type in the address header, e.g.
+// stake_test for a testnet reward address
+func Parse(s string) (Address, error) {
+	if hrp, data, err := bech32.Decode(s); err == nil {
+		addr, err := FromBytes(data)
+		if err != nil {
+			return Address{}, fmt.Errorf("failed to parse address, %v: %w", s, err)
+		}
+		if addr.Type == Byron {
+			return Address{}, fmt.Errorf("failed to parse address, %v: byron address must be base58 encoded", s)
+		}
+		if want := addr.HRP(); !strings.EqualFold(hrp, want) {
+			return Address{}, fmt.Errorf("failed to parse address, %v: got prefix %v; want %v for %v address", s, hrp, want, addr.Type)
+		}
+		return addr, nil
+	}
+
+	data, err := base58.Decode(s)
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to parse address, %v: not bech32 or base58", s)
+	}
+	addr, err := FromBytes(data)
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to parse address, %v: %w", s, err)
+	}
+	if addr.Type != Byron {
+		return Address{}, fmt.Errorf("failed to parse address, %v: shelley address must be bech32 encoded", s)
+	}
+	return addr, nil
+}
+
+// MustParse is like Parse, but panics on error
+func MustParse(s string) Address {
+	addr, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+// FromBytes decodes the raw binary address
+func FromBytes(data []byte) (Address, error) {
+	if len(data) == 0 {
+		return Address{}, fmt.Errorf("empty address")
+	}
+
+	var (
+		header  = data[0]
+		t       = Type(header >> 4)
+		network = header & 0x0f
+		payload = data[1:]
+	)
+
+	credential := func(b []byte, script bool) *Credential {
+		return &Credential{Hash: append([]byte(nil), b...), Script: script}
+	}
+
+	switch {
+	case t.IsBase():
+		if len(payload) != 2*hashSize {
+			return Address{}, fmt.Errorf("invalid base address length, %v", len(data))
+		}
+		return Address{
+			Type:    t,
+			Network: network,
+			Payment: credential(payload[:hashSize], t&0x1 != 0),
+			Stake:   credential(payload[hashSize:], t&0x2 != 0),
+		}, nil
+
+	case t.IsPointer():
+		if len(payload) < hashSize {
+			return Address{}, fmt.Errorf("invalid pointer address length, %v", len(data))
+		}
+		pointer, err := decodePointer(payload[hashSize:])
+		if err != nil {
+			return Address{}, err
+		}
+		return Address{
+			Type:    t,
+			Network: network,
+			Payment: credential(payload[:hashSize], t == PointerScript),
+			Pointer: &pointer,
+		}, nil
+
+	case t.IsEnterprise():
+		if len(payload) != hashSize {
+			return Address{}, fmt.Errorf("invalid enterprise address length, %v", len(data))
+		}
+		return Address{
+			Type:    t,
+			Network: network,
+			Payment: credential(payload, t == EnterpriseScript),
+		}, nil
+
+	case t.IsReward():
+		if len(payload) != hashSize {
+			return Address{}, fmt.Errorf("invalid reward address length, %v", len(data))
+		}
+		return Address{
+			Type:    t,
+			Network: network,
+			Stake:   credential(payload, t == RewardScript),
+		}, nil
+
+	case t == Byron:
+		byron, err := decodeByron(data)
+		if err != nil {
+			return Address{}, err
+		}
+		return Address{
+			Type:    Byron,
+			Network: byron.Network(),
+			Byron:   &byron,
+		}, nil
+
+	default:
+		return Address{}, fmt.Errorf("unknown address type, %v", byte(t))
+	}
+}
+
+// Bytes returns the raw binary address
+func (a Address) Bytes() []byte {
+	if a.Type == Byron {
+		if a.Byron == nil {
+			return nil
+		}
+		return append([]byte(nil), a.Byron.Raw...)
+	}
+
+	b := []byte{byte(a.Type)<<4 | a.Network&0x0f}
+	if a.Payment != nil {
+		b = append(b, a.Payment.Hash...)
+	}
+	switch {
+	case a.Type.IsBase() || a.Type.IsReward():
+		if a.Stake != nil {
+			b = append(b, a.Stake.Hash...)
+		}
+	case a.Type.IsPointer():
+		if a.Pointer != nil {
+			b = appendPointer(b, *a.Pointer)
+		}
+	}
+	return b
+}
+
+// HRP returns the bech32 human readable part for the address
+func (a Address) HRP() string {
+	prefix := "addr"
+	if a.Type.IsReward() {
+		prefix = "stake"
+	}
+	if a.Network != Mainnet {
+		prefix += "_test"
+	}
+	return prefix
+}
+
+// String returns the bech32 encoding of shelley addresses and the base58
+// encoding of byron addresses
+func (a Address) String() string {
+	if a.Type == Byron {
+		return base58.Encode(a.Bytes())
+	}
+	s, err := bech32.Encode(a.HRP(), a.Bytes())
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+func (a Address) Equal(that Address) bool {
+	return bytes.Equal(a.Bytes(), that.Bytes())
+}
+
+// PaymentCredential returns the payment credential of shelley addresses that
+// have one
+func (a Address) PaymentCredential() (Credential, bool) {
+	if a.Payment == nil {
+		return Credential{}, false
+	}
+	return *a.Payment, true
+}
+
+// StakeCredential returns the stake credential of base and reward addresses.
+// Pointer addresses only reference a certificate and so return false
+func (a Address) StakeCredential() (Credential, bool) {
+	if a.Stake == nil {
+		return Credential{}, false
+	}
+	return *a.Stake, true
+}
+
+// StakeAddress returns the reward address that outputs to this address are
+// delegated through; useful for grouping outputs by staking key
+func (a Address) StakeAddress() (Address, bool) {
+	stake, ok := a.StakeCredential()
+	if !ok {
+		return Address{}, false
+	}
+	return NewReward(a.Network, stake), true
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (a Address) MarshalText() ([]byte, error) {
+	if a.Type == Byron && a.Byron == nil {
+		return nil, fmt.Errorf("failed to encode address: missing byron payload")
+	}
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (a *Address) UnmarshalText(data []byte) error {
+	addr, err := Parse(strings.TrimSpace(string(data)))
+	if err != nil {
+		return err
+	}
+	*a = addr
+	return nil
+}
+
+// decodePointer reads the three variable length naturals of a pointer
+func decodePointer(data []byte) (Pointer, error) {
+	var values [3]uint64
+	for i := range values {
+		var (
+			v    uint64
+			done bool
+		)
+		for !done {
+			if len(data) == 0 {
+				return Pointer{}, fmt.Errorf("invalid pointer address: truncated pointer")
+			}
+			if v > (1<<64-1)>>7 {
+				return Pointer{}, fmt.Errorf("invalid pointer address: pointer overflows uint64")
+			}
+			b := data[0]
+			data = data[1:]
+			v = v<<7 | uint64(b&0x7f)
+			done = b&0x80 == 0
+		}
+		values[i] = v
+	}
+	if len(data) > 0 {
+		return Pointer{}, fmt.Errorf("invalid pointer address: %v trailing bytes", len(data))
+	}
+	return Pointer{Slot: values[0], TxIndex: values[1], CertIndex: values[2]}, nil
+}
+
+func appendPointer(b []byte, p Pointer) []byte {
+	for _, v := range []uint64{p.Slot, p.TxIndex, p.CertIndex} {
+		var tmp [10]byte
+		i := len(tmp) - 1
+		tmp[i] = byte(v & 0x7f)
+		for v >>= 7; v > 0; v >>= 7 {
+			i--
+			tmp[i] = byte(v&0x7f) | 0x80
+		}
+		b = append(b, tmp[i:]...)
+	}
+	return b
+}
@@ -0,0 +1,203 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package address
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/bech32"
+	"github.com/stretchr/testify/assert"
+)
+
+// test vectors from CIP-19
+const (
+	paymentKeyHash = "9493315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+	stakeKeyHash   = "337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251"
+	scriptHash     = "c37b1b5dc0669f1d3c61a6fddb2e8fde96be87b881c60bce8e8d542f"
+)
+
+func mustKey(t *testing.T, hash string) Credential {
+	c, err := KeyCredential(hash)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return c
+}
+
+func mustScript(t *testing.T, hash string) Credential {
+	c, err := ScriptCredential(hash)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return c
+}
+
+func TestParse_Shelley(t *testing.T) {
+	var (
+		paymentKey = mustKey(t, paymentKeyHash)
+		stakeKey   = mustKey(t, stakeKeyHash)
+		script     = mustScript(t, scriptHash)
+		pointer    = Pointer{Slot: 2498243, TxIndex: 27, CertIndex: 3}
+	)
+
+	tests := map[string]Address{
+		"addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x":      NewBase(Mainnet, paymentKey, stakeKey),
+		"addr1z8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gten0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs9yc0hh":      NewBase(Mainnet, script, stakeKey),
+		"addr1yx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerkr0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shs2z78ve":      NewBase(Mainnet, paymentKey, script),
+		"addr1x8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gt7r0vd4msrxnuwnccdxlhdjar77j6lg0wypcc9uar5d2shskhj42g":      NewBase(Mainnet, script, script),
+		"addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k":                                          NewPointer(Mainnet, paymentKey, pointer),
+		"addr128phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtupnz75xxcrtw79hu":                                          NewPointer(Mainnet, script, pointer),
+		"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8":                                                   NewEnterprise(Mainnet, paymentKey),
+		"addr1w8phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcyjy7wx":                                                   NewEnterprise(Mainnet, script),
+		"stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw":                                                  NewReward(Mainnet, stakeKey),
+		"stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5":                                                  NewReward(Mainnet, script),
+		"addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgs68faae": NewBase(Testnet, paymentKey, stakeKey),
+		"stake_test1uqehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gssrtvn":                                             NewReward(Testnet, stakeKey),
+	}
+
+	for s, want := range tests {
+		t.Run(s, func(t *testing.T) {
+			addr, err := Parse(s)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, want, addr)
+			assert.Equal(t, s, addr.String())
+			assert.Equal(t, s, want.String())
+
+			decoded, err := FromBytes(addr.Bytes())
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.True(t, addr.Equal(decoded))
+		})
+	}
+}
+
+func TestAddress_StakeAddress(t *testing.T) {
+	addr := MustParse("addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x")
+	assert.Equal(t, Mainnet, addr.Network)
+	assert.True(t, addr.Type.IsBase())
+
+	payment, ok := addr.PaymentCredential()
+	assert.True(t, ok)
+	assert.False(t, payment.Script)
+	assert.Equal(t, paymentKeyHash, payment.Hex())
+
+	stake, ok := addr.StakeAddress()
+	assert.True(t, ok)
+	assert.Equal(t, "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw", stake.String())
+
+	// pointer and enterprise addresses have no stake credential of their own
+	for _, s := range []string{
+		"addr1gx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer5pnz75xxcrzqf96k",
+		"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8",
+	} {
+		_, ok := MustParse(s).StakeAddress()
+		assert.False(t, ok)
+	}
+}
+
+func TestParse_Byron(t *testing.T) {
+	tests := map[string]byte{
+		"Ae2tdPwUPEZFRbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAi":                                                        Mainnet,
+		"37btjrVyb4KEB2STADSsj3MYSAdj52X5FrFWpw2r7Wmj2GDzXjFRsHWuZqrw7zSkwopv8Ci3VWeg6bisU9dgJxW5hb2MZYeduNKbQJrqz3zVBsu9nT": Testnet,
+	}
+
+	for s, network := range tests {
+		addr, err := Parse(s)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, Byron, addr.Type)
+		assert.Equal(t, network, addr.Network)
+		assert.Len(t, addr.Byron.Root, 28)
+		assert.Equal(t, s, addr.String())
+
+		_, ok := addr.PaymentCredential()
+		assert.False(t, ok)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3y", // checksum
+		"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerszqtyh8",                                              // truncated
+		"Ae2tdPwUPEZFRbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAj",                                             // crc
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("got nil; want err for %v", s)
+		}
+	}
+}
+
+func TestParse_Prefix(t *testing.T) {
+	var (
+		base    = NewBase(Mainnet, mustKey(t, paymentKeyHash), mustKey(t, stakeKeyHash))
+		reward  = NewReward(Mainnet, mustKey(t, stakeKeyHash))
+		testnet = NewEnterprise(Testnet, mustKey(t, paymentKeyHash))
+	)
+
+	tests := []struct {
+		HRP     string
+		Address Address
+	}{
+		{HRP: "stake", Address: base},
+		{HRP: "addr_test", Address: base},
+		{HRP: "addr", Address: reward},
+		{HRP: "stake_test", Address: reward},
+		{HRP: "addr", Address: testnet},
+	}
+
+	for _, tc := range tests {
+		s, err := bech32.Encode(tc.HRP, tc.Address.Bytes())
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("got nil; want err for %v with prefix %v", tc.Address.Type, tc.HRP)
+		}
+	}
+
+	addr, err := bech32.Encode("addr_test", testnet.Bytes())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if _, err := Parse(addr); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+}
+
+func TestAddress_JSON(t *testing.T) {
+	type Item struct {
+		Address Address `json:"address"`
+	}
+
+	const s = `{"address":"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"}`
+
+	var item Item
+	if err := json.Unmarshal([]byte(s), &item); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.True(t, item.Address.Type.IsEnterprise())
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, s, string(data))
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package address
+
+import (
+	"fmt"
+	"hash/crc32"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	byronAttrDerivationPath = 1
+	byronAttrProtocolMagic  = 2
+	byronPayloadTag         = 24
+)
+
+// ByronType identifies the spending data of a byron address
+type ByronType uint64
+
+const (
+	ByronPubKey ByronType = 0
+	ByronScript ByronType = 1
+	ByronRedeem ByronType = 2
+)
+
+// ByronAddress holds the decoded contents of a byron address
+type ByronAddress struct {
+	// Raw is the complete binary address, including checksum
+	Raw []byte
+	// Root is the blake2b-224 hash of the address spending data
+	Root []byte
+	Type ByronType
+	// DerivationPath is the encrypted hd derivation path, if present
+	DerivationPath []byte
+	// ProtocolMagic is set for addresses outside of mainnet
+	ProtocolMagic *uint32
+}
+
+// Network returns Mainnet unless the address carries a protocol magic
+func (b ByronAddress) Network() byte {
+	if b.ProtocolMagic != nil {
+		return Testnet
+	}
+	return Mainnet
+}
+
+type byronEnvelope struct {
+	_       struct{} `cbor:",toarray"`
+	Payload cbor.Tag
+	CRC     uint32
+}
+
+type byronPayload struct {
+	_          struct{} `cbor:",toarray"`
+	Root       []byte
+	Attributes map[uint64]cbor.RawMessage
+	Type       uint64
+}
+
+func decodeByron(data []byte) (ByronAddress, error) {
+	var envelope byronEnvelope
+	if err := cbor.Unmarshal(data, &envelope); err != nil {
+		return ByronAddress{}, fmt.Errorf("invalid byron address: %w", err)
+	}
+	if envelope.Payload.Number != byronPayloadTag {
+		return ByronAddress{}, fmt.Errorf("invalid byron address: got tag %v; want %v", envelope.Payload.Number, byronPayloadTag)
+	}
+	raw, ok := envelope.Payload.Content.([]byte)
+	if !ok {
+		return ByronAddress{}, fmt.Errorf("invalid byron address: payload is not a byte string")
+	}
+	if got, want := crc32.ChecksumIEEE(raw), envelope.CRC; got != want {
+		return ByronAddress{}, fmt.Errorf("invalid byron address: checksum mismatch")
+	}
+
+	var payload byronPayload
+	if err := cbor.Unmarshal(raw, &payload); err != nil {
+		return ByronAddress{}, fmt.Errorf("invalid byron address payload: %w", err)
+	}
+	if len(payload.Root) != hashSize {
+		return ByronAddress{}, fmt.Errorf("invalid byron address root length, %v", len(payload.Root))
+	}
+
+	addr := ByronAddress{
+		Raw:  append([]byte(nil), data...),
+		Root: payload.Root,
+		Type: ByronType(payload.Type),
+	}
+	if v, ok := payload.Attributes[byronAttrDerivationPath]; ok {
+		if err := cbor.Unmarshal(v, &addr.DerivationPath); err != nil {
+			return ByronAddress{}, fmt.Errorf("invalid byron address derivation path: %w", err)
+		}
+	}
+	if v, ok := payload.Attributes[byronAttrProtocolMagic]; ok {
+		// the protocol magic is itself cbor encoded within a byte string
+		var encoded []byte
+		if err := cbor.Unmarshal(v, &encoded); err != nil {
+			return ByronAddress{}, fmt.Errorf("invalid byron address protocol magic: %w", err)
+		}
+		var magic uint32
+		if err := cbor.Unmarshal(encoded, &magic); err != nil {
+			return ByronAddress{}, fmt.Errorf("invalid byron address protocol magic: %w", err)
+		}
+		addr.ProtocolMagic = &magic
+	}
+	return addr, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/bech32"
+)
+
+const (
+	// PolicyIDSize is the length in bytes of a minting policy hash
+	PolicyIDSize = 28
+	// MaxAssetNameSize is the maximum length in bytes of an asset name
+	MaxAssetNameSize = 32
+
+	fingerprintHRP  = "asset"
+	fingerprintSize = 20
+	cip67LabelSize  = 4
+)
+
+// CIP-67 asset name labels used by CIP-68 tokens
+const (
+	CIP68ReferenceLabel = 100 // reference nft holding the datum
+	CIP68NFTLabel       = 222 // user nft
+	CIP68FTLabel        = 333 // user fungible token
+	CIP68RFTLabel       = 444 // user rich fungible token
+)
+
+// NewAssetID returns the asset id for the hex encoded policy id and asset
+// name; an empty name yields the bare policy id
+func NewAssetID(policyID, assetName string) (AssetID, error) {
+	policy, err := hex.DecodeString(policyID)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode policy id, %v: %w", policyID, err)
+	}
+	if len(policy) != PolicyIDSize {
+		return "", fmt.Errorf("invalid policy id, %v: got %v bytes; want %v", policyID, len(policy), PolicyIDSize)
+	}
+
+	name, err := hex.DecodeString(assetName)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode asset name, %v: %w", assetName, err)
+	}
+	if len(name) > MaxAssetNameSize {
+		return "", fmt.Errorf("invalid asset name, %v: got %v bytes; want at most %v", assetName, len(name), MaxAssetNameSize)
+	}
+
+	policyID, assetName = strings.ToLower(policyID), strings.ToLower(assetName)
+	if assetName == "" {
+		return AssetID(policyID), nil
+	}
+	return AssetID(policyID + "." + assetName), nil
+}
+
+// Validate verifies the policy id and asset name are hex encoded and of
+// valid length
+func (a AssetID) Validate() error {
+	s := string(a)
+	if index := strings.Index(s, "."); index >= 0 && index == len(s)-1 {
+		return fmt.Errorf("invalid asset id, %v: empty asset name after separator", s)
+	}
+	if _, err := NewAssetID(a.PolicyID(), a.AssetName()); err != nil {
+		return fmt.Errorf("invalid asset id, %v: %w", s, err)
+	}
+	return nil
+}
+
+// Fingerprint returns the CIP-14 asset fingerprint, asset1...
+func (a AssetID) Fingerprint() (string, error) {
+	policy, err := hex.DecodeString(a.PolicyID())
+	if err != nil {
+		return "", fmt.Errorf("failed to compute fingerprint for asset, %v: %w", a, err)
+	}
+	name, err := hex.DecodeString(a.AssetName())
+	if err != nil {
+		return "", fmt.Errorf("failed to compute fingerprint for asset, %v: %w", a, err)
+	}
+
+	h, err := blake2b.New(fingerprintSize, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute fingerprint for asset, %v: %w", a, err)
+	}
+	h.Write(policy)
+	h.Write(name)
+
+	return bech32.Encode(fingerprintHRP, h.Sum(nil))
+}
+
+// MatchFingerprint returns true if the asset has the provided CIP-14 fingerprint
+func (a AssetID) MatchFingerprint(fingerprint string) bool {
+	got, err := a.Fingerprint()
+	return err == nil && got == strings.ToLower(fingerprint)
+}
+
+// ParseFingerprint validates a CIP-14 fingerprint and returns the underlying
+// blake2b-160 hash of the policy id and asset name
+func ParseFingerprint(fingerprint string) ([]byte, error) {
+	hrp, data, err := bech32.Decode(fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse asset fingerprint, %v: %w", fingerprint, err)
+	}
+	if hrp != fingerprintHRP {
+		return nil, fmt.Errorf("failed to parse asset fingerprint, %v: got prefix %v; want %v", fingerprint, hrp, fingerprintHRP)
+	}
+	if len(data) != fingerprintSize {
+		return nil, fmt.Errorf("failed to parse asset fingerprint, %v: got %v bytes; want %v", fingerprint, len(data), fingerprintSize)
+	}
+	return data, nil
+}
+
+// CIP67Label returns the hex encoded 4 byte CIP-67 asset name prefix for label
+func CIP67Label(label uint16) string {
+	b := []byte{byte(label >> 8), byte(label)}
+	return fmt.Sprintf("0%04x%02x0", label, crc8(b))
+}
+
+// Label returns the CIP-67 label prefixing the asset name, if any
+func (a AssetID) Label() (uint16, bool) {
+	name, err := hex.DecodeString(a.AssetName())
+	if err != nil || len(name) < cip67LabelSize {
+		return 0, false
+	}
+	if name[0]&0xf0 != 0 || name[3]&0x0f != 0 {
+		return 0, false
+	}
+
+	label := uint16(name[0])<<12 | uint16(name[1])<<4 | uint16(name[2])>>4
+	checksum := name[2]<<4 | name[3]>>4
+	if crc8([]byte{byte(label >> 8), byte(label)}) != checksum {
+		return 0, false
+	}
+	return label, true
+}
+
+// WithLabel returns the asset with the same policy and name content, but
+// with its CIP-67 label replaced; e.g. the (100) reference token of a (222) nft
+func (a AssetID) WithLabel(label uint16) (AssetID, error) {
+	name := a.AssetName()
+	if _, ok := a.Label(); ok {
+		name = name[2*cip67LabelSize:]
+	}
+	return NewAssetID(a.PolicyID(), CIP67Label(label)+name)
+}
+
+// crc8 computes the CRC-8 checksum, polynomial 0x07, used by CIP-67
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetID_Fingerprint(t *testing.T) {
+	// test vectors from CIP-14
+	tests := []struct {
+		policyID    string
+		assetName   string
+		fingerprint string
+	}{
+		{
+			policyID:    "7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373",
+			fingerprint: "asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3",
+		},
+		{
+			policyID:    "7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc37e",
+			fingerprint: "asset1nl0puwxmhas8fawxp8nx4e2q3wekg969n2auw3",
+		},
+		{
+			policyID:    "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209",
+			assetName:   "504154415445",
+			fingerprint: "asset1hv4p5tv2a837mzqrst04d0dcptdjmluqvdx9k3",
+		},
+		{
+			policyID:    "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209",
+			assetName:   "7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373",
+			fingerprint: "asset1aqrdypg669jgazruv5ah07nuyqe0wxjhe2el6f",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.fingerprint, func(t *testing.T) {
+			assetID, err := NewAssetID(tc.policyID, tc.assetName)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			got, err := assetID.Fingerprint()
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, tc.fingerprint, got)
+			assert.True(t, assetID.MatchFingerprint(tc.fingerprint))
+
+			hash, err := ParseFingerprint(tc.fingerprint)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Len(t, hash, 20)
+		})
+	}
+
+	_, err := ParseFingerprint("addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8")
+	assert.NotNil(t, err)
+}
+
+func TestNewAssetID(t *testing.T) {
+	const policyID = "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209"
+
+	assetID, err := NewAssetID(policyID, "")
+	assert.Nil(t, err)
+	assert.Equal(t, AssetID(policyID), assetID)
+
+	assetID, err = NewAssetID(policyID, "504154415445")
+	assert.Nil(t, err)
+	assert.Equal(t, AssetID(policyID+".504154415445"), assetID)
+	assert.Nil(t, assetID.Validate())
+
+	for _, tc := range [][2]string{
+		{policyID[:54], ""},
+		{policyID, "zz"},
+		{policyID, policyID + policyID[:10]},
+	} {
+		_, err := NewAssetID(tc[0], tc[1])
+		assert.NotNil(t, err)
+	}
+
+	assert.NotNil(t, AssetID(policyID+".").Validate())
+	assert.NotNil(t, AssetID("abc.def").Validate())
+}
+
+func TestAssetID_Label(t *testing.T) {
+	assert.Equal(t, "000643b0", CIP67Label(CIP68ReferenceLabel))
+	assert.Equal(t, "000de140", CIP67Label(CIP68NFTLabel))
+	assert.Equal(t, "0014df10", CIP67Label(CIP68FTLabel))
+	assert.Equal(t, "001bc280", CIP67Label(CIP68RFTLabel))
+
+	const policyID = "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209"
+
+	nft := AssetID(policyID + ".000de140" + "504154415445")
+	label, ok := nft.Label()
+	assert.True(t, ok)
+	assert.EqualValues(t, CIP68NFTLabel, label)
+
+	ref, err := nft.WithLabel(CIP68ReferenceLabel)
+	assert.Nil(t, err)
+	assert.Equal(t, AssetID(policyID+".000643b0504154415445"), ref)
+
+	_, ok = AssetID(policyID + ".504154415445").Label()
+	assert.False(t, ok)
+	_, ok = AssetID(policyID + ".000de141").Label() // bad checksum
+	assert.False(t, ok)
+}
@@ -16,6 +16,12 @@ package chainsync
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 )
 
 type ByronBlock struct {
@@ -24,10 +30,28 @@ type ByronBlock struct {
 	Header ByronHeader
 }
 
+// Block converts the byron block into the shape shared by later eras
+func (b ByronBlock) Block() *Block {
+	var txs []Tx
+	for _, payload := range b.Body.TxPayload {
+		txs = append(txs, payload.Tx())
+	}
+
+	return &Block{
+		Body: txs,
+		Header: BlockHeader{
+			BlockHeight: b.Header.BlockHeight,
+			PrevHash:    b.Header.PrevHash,
+			Slot:        b.Header.Slot,
+		},
+		HeaderHash: b.Hash,
+	}
+}
+
 type ByronBody struct {
-	DlgPayload    []json.RawMessage `json:"dlgPayload,omitempty"`
-	TxPayload     []ByronTxPayload  `json:"txPayload,omitempty"`
-	UpdatePayload json.RawMessage   `json:"updatePayload,omitempty"`
+	DlgPayload    []ByronDelegation   `json:"dlgPayload,omitempty"`
+	TxPayload     []ByronTxPayload    `json:"txPayload,omitempty"`
+	UpdatePayload *ByronUpdatePayload `json:"updatePayload,omitempty"`
 }
 
 type ByronHeader struct {
@@ -50,9 +74,148 @@ type ByronTxBody struct {
 
 type ByronTxPayload struct {
 	ID      string
+	Body    ByronTxBody
 	Witness []ByronWitness
 }
 
+// Tx returns the byron transaction in the same shape as later eras so utxos
+// may be tracked uniformly from genesis
+func (b ByronTxPayload) Tx() Tx {
+	return Tx{
+		ID: b.ID,
+		Body: TxBody{
+			Inputs:  b.Body.Inputs,
+			Outputs: b.Body.Outputs,
+		},
+	}
+}
+
+// ByronWitness is either a regular or a redeem key witness; exactly one field
+// will be set
 type ByronWitness struct {
-	RedeemWitness map[string]string
+	WitnessVk     *ByronKeyWitness `json:"witnessVk,omitempty"`
+	RedeemWitness *ByronKeyWitness `json:"redeemWitness,omitempty"`
+}
+
+type ByronKeyWitness struct {
+	Key       string `json:"key"`
+	Signature string `json:"signature"`
+}
+
+// ByronDelegation is a heavyweight delegation certificate
+type ByronDelegation struct {
+	Epoch                   uint64 `json:"epoch"`
+	IssuerVerificationKey   string `json:"issuerVerificationKey"`
+	DelegateVerificationKey string `json:"delegateVerificationKey"`
+	Signature               string `json:"signature"`
+}
+
+// byronDelegation allows the default dynamodb decoding to be used
+type byronDelegation ByronDelegation
+
+func (b *ByronDelegation) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, delegations were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, b); err != nil {
+			return fmt.Errorf("failed to unmarshal byron delegation: %w", err)
+		}
+		return nil
+	}
+
+	var v byronDelegation
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal byron delegation: %w", err)
+	}
+	*b = ByronDelegation(v)
+	return nil
+}
+
+type ByronUpdatePayload struct {
+	Proposal *ByronUpdateProposal `json:"proposal,omitempty"`
+	Votes    []ByronUpdateVote    `json:"votes,omitempty"`
+}
+
+// byronUpdatePayload allows the default dynamodb decoding to be used
+type byronUpdatePayload ByronUpdatePayload
+
+func (b *ByronUpdatePayload) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, update payloads were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, b); err != nil {
+			return fmt.Errorf("failed to unmarshal byron update payload: %w", err)
+		}
+		return nil
+	}
+
+	var v byronUpdatePayload
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal byron update payload: %w", err)
+	}
+	*b = ByronUpdatePayload(v)
+	return nil
+}
+
+type ByronUpdateProposal struct {
+	Body      ByronUpdateProposalBody `json:"body"`
+	Issuer    string                  `json:"issuer"`
+	Signature string                  `json:"signature"`
+}
+
+type ByronUpdateProposalBody struct {
+	ProtocolVersion  ProtocolVersion         `json:"protocolVersion"`
+	ParametersUpdate ByronProtocolParameters `json:"parametersUpdate"`
+	SoftwareVersion  ByronSoftwareVersion    `json:"softwareVersion"`
+	Metadata         map[string]interface{}  `json:"metadata,omitempty"`
+}
+
+type ByronSoftwareVersion struct {
+	AppName string `json:"appName"`
+	Number  uint32 `json:"number"`
+}
+
+type ByronUpdateVote struct {
+	Voter      string `json:"voter"`
+	ProposalID string `json:"proposalId"`
+	Signature  string `json:"signature"`
+}
+
+// ByronProtocolParameters holds the proposed byron parameters; only the
+// parameters being changed are set.  Thresholds are lovelace portions scaled
+// by 10^15
+type ByronProtocolParameters struct {
+	HeavyDelThd       *uint64            `json:"heavyDelThd,omitempty"`
+	MaxBlockSize      *uint64            `json:"maxBlockSize,omitempty"`
+	MaxHeaderSize     *uint64            `json:"maxHeaderSize,omitempty"`
+	MaxProposalSize   *uint64            `json:"maxProposalSize,omitempty"`
+	MaxTxSize         *uint64            `json:"maxTxSize,omitempty"`
+	MpcThd            *uint64            `json:"mpcThd,omitempty"`
+	ScriptVersion     *uint64            `json:"scriptVersion,omitempty"`
+	SlotDuration      *uint64            `json:"slotDuration,omitempty"` // milliseconds
+	SoftforkRule      *ByronSoftforkRule `json:"softforkRule,omitempty"`
+	TxFeePolicy       *ByronTxFeePolicy  `json:"txFeePolicy,omitempty"`
+	UnlockStakeEpoch  *uint64            `json:"unlockStakeEpoch,omitempty"`
+	UpdateImplicit    *uint64            `json:"updateImplicit,omitempty"`
+	UpdateProposalThd *uint64            `json:"updateProposalThd,omitempty"`
+	UpdateVoteThd     *uint64            `json:"updateVoteThd,omitempty"`
+}
+
+type ByronSoftforkRule struct {
+	InitThd      uint64 `json:"initThd"`
+	MinThd       uint64 `json:"minThd"`
+	ThdDecrement uint64 `json:"thdDecrement"`
+}
+
+// ByronTxFeePolicy computes the minimum fee as constant + coefficient * size,
+// both scaled by 10^9
+type ByronTxFeePolicy struct {
+	Coefficient num.Int `json:"coefficient"`
+	Constant    num.Int `json:"constant"`
 }
@@ -0,0 +1,148 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+const byronBlockJSON = `{
+  "byron": {
+    "hash": "f0f7892b5c333cffc4b3c4344de48af4cc63f55e44936196f365a9ef2244134f",
+    "header": {
+      "blockHeight": 4,
+      "genesisKey": "0bdb1f5ef3d994037593f1ba7233c66b73a24ddc9d1f6ae4db5b4195498a7c07b5d1ee4a1d2e07d4e3f4b8d2fbcb0c8d5f9e1b6a0c3e2d1f0a9b8c7d6e5f4a3b2",
+      "epoch": 0,
+      "proof": {},
+      "prevHash": "89d9b5a5b8ddc8d7e5a6e8d2d7c3f7e1a4b9c5e2d6f1a7b3c8e4d9f2a6b1c5e7",
+      "protocolMagicId": 764824073,
+      "protocolVersion": {"major": 0, "minor": 0, "patch": 0},
+      "signature": {},
+      "slot": 4,
+      "softwareVersion": {"appName": "cardano-sl", "number": 1}
+    },
+    "body": {
+      "txPayload": [{
+        "id": "a9e4413a5fb61a7a43c7df006ffcaaf3f2ffc9541f54757023968c5a8f8294fd",
+        "body": {
+          "inputs": [{"txId": "6c3c6d9e1c3e4b0b6f3a2f1e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c", "index": 1}],
+          "outputs": [{"address": "DdzFFzCqrhsjcfsReoiHddcLjiSmMmAUcGQMTAMCdkbM1ha6NnoHMvaZUGhKS5aJLQcpxhw1XnLXhbYrQTtEgL2okrxJLK6rsmN3AnAh", "value": {"coins": 1000000}}]
+        },
+        "witness": [
+          {"witnessVk": {"key": "a1b2", "signature": "c3d4"}},
+          {"redeemWitness": {"key": "e5f6", "signature": "a7b8"}}
+        ]
+      }],
+      "dlgPayload": [{
+        "epoch": 1,
+        "issuerVerificationKey": "aa",
+        "delegateVerificationKey": "bb",
+        "signature": "cc"
+      }],
+      "updatePayload": {
+        "proposal": {
+          "body": {
+            "protocolVersion": {"major": 1, "minor": 0, "patch": 0},
+            "parametersUpdate": {
+              "maxTxSize": 8192,
+              "softforkRule": {"initThd": 900000000000000, "minThd": 600000000000000, "thdDecrement": 50000000000000},
+              "txFeePolicy": {"coefficient": 43946000000, "constant": 155381000000000}
+            },
+            "softwareVersion": {"appName": "cardano-sl", "number": 2}
+          },
+          "issuer": "dd",
+          "signature": "ee"
+        },
+        "votes": [{"voter": "ff", "proposalId": "01", "signature": "02"}]
+      }
+    }
+  }
+}`
+
+func TestByronBlock(t *testing.T) {
+	var block RollForwardBlock
+	err := json.Unmarshal([]byte(byronBlockJSON), &block)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if block.Byron == nil {
+		t.Fatalf("got nil; want byron block")
+	}
+
+	body := block.Byron.Body
+	assert.Len(t, body.TxPayload, 1)
+
+	tx := body.TxPayload[0].Tx()
+	assert.Equal(t, "a9e4413a5fb61a7a43c7df006ffcaaf3f2ffc9541f54757023968c5a8f8294fd", tx.ID)
+	assert.Equal(t, 1, tx.Body.Inputs[0].Index)
+	assert.Equal(t, "1000000", tx.Body.Outputs[0].Value.Coins.String())
+
+	witness := body.TxPayload[0].Witness
+	assert.Equal(t, "a1b2", witness[0].WitnessVk.Key)
+	assert.Nil(t, witness[0].RedeemWitness)
+	assert.Equal(t, "a7b8", witness[1].RedeemWitness.Signature)
+
+	assert.Equal(t, []ByronDelegation{{
+		Epoch:                   1,
+		IssuerVerificationKey:   "aa",
+		DelegateVerificationKey: "bb",
+		Signature:               "cc",
+	}}, body.DlgPayload)
+
+	proposal := body.UpdatePayload.Proposal
+	assert.EqualValues(t, 1, proposal.Body.ProtocolVersion.Major)
+	assert.EqualValues(t, 8192, *proposal.Body.ParametersUpdate.MaxTxSize)
+	assert.Nil(t, proposal.Body.ParametersUpdate.MaxBlockSize)
+	assert.EqualValues(t, 50000000000000, proposal.Body.ParametersUpdate.SoftforkRule.ThdDecrement)
+	assert.Equal(t, "155381000000000", proposal.Body.ParametersUpdate.TxFeePolicy.Constant.String())
+	assert.Equal(t, "cardano-sl", proposal.Body.SoftwareVersion.AppName)
+	assert.Len(t, body.UpdatePayload.Votes, 1)
+
+	item, err := dynamodbattribute.Marshal(block)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got RollForwardBlock
+	err = dynamodbattribute.Unmarshal(item, &got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, body, got.Byron.Body)
+}
+
+func TestByronBody_LegacyDynamoDB(t *testing.T) {
+	// delegations and update payloads were previously stored as raw json
+	item := &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"DlgPayload": {L: []*dynamodb.AttributeValue{
+			{B: []byte(`{"epoch": 1, "issuerVerificationKey": "aa", "delegateVerificationKey": "bb", "signature": "cc"}`)},
+		}},
+		"UpdatePayload": {B: []byte(`{"proposal": null, "votes": [{"voter": "ff", "proposalId": "01", "signature": "02"}]}`)},
+	}}
+
+	var body ByronBody
+	err := dynamodbattribute.Unmarshal(item, &body)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, "bb", body.DlgPayload[0].DelegateVerificationKey)
+	assert.Nil(t, body.UpdatePayload.Proposal)
+	assert.Equal(t, "ff", body.UpdatePayload.Votes[0].Voter)
+}
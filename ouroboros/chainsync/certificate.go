@@ -0,0 +1,240 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// CertificateType identifies the kind of certificate held by a Certificate
+type CertificateType string
+
+const (
+	CertificateTypeUnknown                 CertificateType = ""
+	CertificateTypeStakeKeyRegistration    CertificateType = "stakeKeyRegistration"
+	CertificateTypeStakeKeyDeregistration  CertificateType = "stakeKeyDeregistration"
+	CertificateTypeStakeDelegation         CertificateType = "stakeDelegation"
+	CertificateTypePoolRegistration        CertificateType = "poolRegistration"
+	CertificateTypePoolRetirement          CertificateType = "poolRetirement"
+	CertificateTypeGenesisDelegation       CertificateType = "genesisDelegation"
+	CertificateTypeMoveInstantaneousReward CertificateType = "moveInstantaneousRewards"
+
+	// conway
+	CertificateTypeStakeCredentialRegistration     CertificateType = "stakeCredentialRegistration"
+	CertificateTypeStakeCredentialDeregistration   CertificateType = "stakeCredentialDeregistration"
+	CertificateTypeVoteDelegation                  CertificateType = "voteDelegation"
+	CertificateTypeStakeVoteDelegation             CertificateType = "stakeVoteDelegation"
+	CertificateTypeStakeRegistrationDelegation     CertificateType = "stakeRegistrationDelegation"
+	CertificateTypeVoteRegistrationDelegation      CertificateType = "voteRegistrationDelegation"
+	CertificateTypeStakeVoteRegistrationDelegation CertificateType = "stakeVoteRegistrationDelegation"
+	CertificateTypeCommitteeHotKeyAuthorization    CertificateType = "committeeHotKeyAuthorization"
+	CertificateTypeCommitteeColdKeyResignation     CertificateType = "committeeColdKeyResignation"
+	CertificateTypeDRepRegistration                CertificateType = "drepRegistration"
+	CertificateTypeDRepDeregistration              CertificateType = "drepDeregistration"
+	CertificateTypeDRepUpdate                      CertificateType = "drepUpdate"
+)
+
+// Certificate is a tagged union; exactly one field will be set
+type Certificate struct {
+	StakeKeyRegistration     string                   `json:"stakeKeyRegistration,omitempty"     dynamodbav:"stakeKeyRegistration,omitempty"`
+	StakeKeyDeregistration   string                   `json:"stakeKeyDeregistration,omitempty"   dynamodbav:"stakeKeyDeregistration,omitempty"`
+	StakeDelegation          *StakeDelegation         `json:"stakeDelegation,omitempty"          dynamodbav:"stakeDelegation,omitempty"`
+	PoolRegistration         *PoolParameters          `json:"poolRegistration,omitempty"         dynamodbav:"poolRegistration,omitempty"`
+	PoolRetirement           *PoolRetirement          `json:"poolRetirement,omitempty"           dynamodbav:"poolRetirement,omitempty"`
+	GenesisDelegation        *GenesisDelegation       `json:"genesisDelegation,omitempty"        dynamodbav:"genesisDelegation,omitempty"`
+	MoveInstantaneousRewards *MoveInstantaneousReward `json:"moveInstantaneousRewards,omitempty" dynamodbav:"moveInstantaneousRewards,omitempty"`
+
+	// conway
+	StakeCredentialRegistration     *StakeCredentialDeposit `json:"stakeCredentialRegistration,omitempty"     dynamodbav:"stakeCredentialRegistration,omitempty"`
+	StakeCredentialDeregistration   *StakeCredentialDeposit `json:"stakeCredentialDeregistration,omitempty"   dynamodbav:"stakeCredentialDeregistration,omitempty"`
+	VoteDelegation                  *Delegation             `json:"voteDelegation,omitempty"                  dynamodbav:"voteDelegation,omitempty"`
+	StakeVoteDelegation             *Delegation             `json:"stakeVoteDelegation,omitempty"             dynamodbav:"stakeVoteDelegation,omitempty"`
+	StakeRegistrationDelegation     *Delegation             `json:"stakeRegistrationDelegation,omitempty"     dynamodbav:"stakeRegistrationDelegation,omitempty"`
+	VoteRegistrationDelegation      *Delegation             `json:"voteRegistrationDelegation,omitempty"      dynamodbav:"voteRegistrationDelegation,omitempty"`
+	StakeVoteRegistrationDelegation *Delegation             `json:"stakeVoteRegistrationDelegation,omitempty" dynamodbav:"stakeVoteRegistrationDelegation,omitempty"`
+	CommitteeHotKeyAuthorization    *CommitteeAuthorization `json:"committeeHotKeyAuthorization,omitempty"    dynamodbav:"committeeHotKeyAuthorization,omitempty"`
+	CommitteeColdKeyResignation     *CommitteeResignation   `json:"committeeColdKeyResignation,omitempty"     dynamodbav:"committeeColdKeyResignation,omitempty"`
+	DRepRegistration                *DRepRegistration       `json:"drepRegistration,omitempty"                dynamodbav:"drepRegistration,omitempty"`
+	DRepDeregistration              *DRepRegistration       `json:"drepDeregistration,omitempty"              dynamodbav:"drepDeregistration,omitempty"`
+	DRepUpdate                      *DRepRegistration       `json:"drepUpdate,omitempty"                      dynamodbav:"drepUpdate,omitempty"`
+
+	// Raw holds the json of a certificate whose kind is not recognized so it
+	// is written back unchanged
+	Raw json.RawMessage `json:"-" dynamodbav:"-"`
+}
+
+// Type returns the kind of certificate
+func (c Certificate) Type() CertificateType {
+	switch {
+	case c.StakeKeyRegistration != "":
+		return CertificateTypeStakeKeyRegistration
+	case c.StakeKeyDeregistration != "":
+		return CertificateTypeStakeKeyDeregistration
+	case c.StakeDelegation != nil:
+		return CertificateTypeStakeDelegation
+	case c.PoolRegistration != nil:
+		return CertificateTypePoolRegistration
+	case c.PoolRetirement != nil:
+		return CertificateTypePoolRetirement
+	case c.GenesisDelegation != nil:
+		return CertificateTypeGenesisDelegation
+	case c.MoveInstantaneousRewards != nil:
+		return CertificateTypeMoveInstantaneousReward
+	case c.StakeCredentialRegistration != nil:
+		return CertificateTypeStakeCredentialRegistration
+	case c.StakeCredentialDeregistration != nil:
+		return CertificateTypeStakeCredentialDeregistration
+	case c.VoteDelegation != nil:
+		return CertificateTypeVoteDelegation
+	case c.StakeVoteDelegation != nil:
+		return CertificateTypeStakeVoteDelegation
+	case c.StakeRegistrationDelegation != nil:
+		return CertificateTypeStakeRegistrationDelegation
+	case c.VoteRegistrationDelegation != nil:
+		return CertificateTypeVoteRegistrationDelegation
+	case c.StakeVoteRegistrationDelegation != nil:
+		return CertificateTypeStakeVoteRegistrationDelegation
+	case c.CommitteeHotKeyAuthorization != nil:
+		return CertificateTypeCommitteeHotKeyAuthorization
+	case c.CommitteeColdKeyResignation != nil:
+		return CertificateTypeCommitteeColdKeyResignation
+	case c.DRepRegistration != nil:
+		return CertificateTypeDRepRegistration
+	case c.DRepDeregistration != nil:
+		return CertificateTypeDRepDeregistration
+	case c.DRepUpdate != nil:
+		return CertificateTypeDRepUpdate
+	default:
+		return CertificateTypeUnknown
+	}
+}
+
+// certificate allows the default json and dynamodb encoding to be used
+type certificate Certificate
+
+func (c Certificate) MarshalJSON() ([]byte, error) {
+	if len(c.Raw) > 0 && c.Type() == CertificateTypeUnknown {
+		return c.Raw, nil
+	}
+	return json.Marshal(certificate(c))
+}
+
+func (c *Certificate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var v certificate
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal certificate: %w", err)
+	}
+	*c = Certificate(v)
+	if c.Type() == CertificateTypeUnknown {
+		c.Raw = append(json.RawMessage(nil), data...)
+	}
+	return nil
+}
+
+// MarshalDynamoDBAttributeValue stores unrecognized certificates as raw json
+func (c Certificate) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if len(c.Raw) > 0 && c.Type() == CertificateTypeUnknown {
+		item.B = c.Raw
+		return nil
+	}
+
+	v, err := dynamodbattribute.Marshal(certificate(c))
+	if err != nil {
+		return fmt.Errorf("failed to marshal certificate: %w", err)
+	}
+	*item = *v
+	return nil
+}
+
+func (c *Certificate) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, certificates were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, c); err != nil {
+			return fmt.Errorf("failed to unmarshal certificate: %w", err)
+		}
+		return nil
+	}
+
+	var v certificate
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal certificate: %w", err)
+	}
+	*c = Certificate(v)
+	return nil
+}
+
+type StakeDelegation struct {
+	Delegator string `json:"delegator,omitempty" dynamodbav:"delegator,omitempty"`
+	Delegatee string `json:"delegatee,omitempty" dynamodbav:"delegatee,omitempty"`
+}
+
+type PoolParameters struct {
+	ID            string        `json:"id,omitempty"            dynamodbav:"id,omitempty"`
+	VRF           string        `json:"vrf,omitempty"           dynamodbav:"vrf,omitempty"`
+	Pledge        num.Int       `json:"pledge"                  dynamodbav:"pledge"`
+	Cost          num.Int       `json:"cost"                    dynamodbav:"cost"`
+	Margin        Ratio         `json:"margin"                  dynamodbav:"margin"`
+	RewardAccount string        `json:"rewardAccount,omitempty" dynamodbav:"rewardAccount,omitempty"`
+	Owners        []string      `json:"owners,omitempty"        dynamodbav:"owners,omitempty"`
+	Relays        []Relay       `json:"relays,omitempty"        dynamodbav:"relays,omitempty"`
+	Metadata      *PoolMetadata `json:"metadata,omitempty"      dynamodbav:"metadata,omitempty"`
+}
+
+// Relay describes how to reach a stake pool; either by address (IPv4 and/or
+// IPv6) or by hostname
+type Relay struct {
+	IPv4     *string `json:"ipv4,omitempty"     dynamodbav:"ipv4,omitempty"`
+	IPv6     *string `json:"ipv6,omitempty"     dynamodbav:"ipv6,omitempty"`
+	Hostname *string `json:"hostname,omitempty" dynamodbav:"hostname,omitempty"`
+	Port     *uint16 `json:"port,omitempty"     dynamodbav:"port,omitempty"`
+}
+
+type PoolMetadata struct {
+	URL  string `json:"url,omitempty"  dynamodbav:"url,omitempty"`
+	Hash string `json:"hash,omitempty" dynamodbav:"hash,omitempty"`
+}
+
+type PoolRetirement struct {
+	PoolID          string `json:"poolId,omitempty"          dynamodbav:"poolId,omitempty"`
+	RetirementEpoch uint64 `json:"retirementEpoch,omitempty" dynamodbav:"retirementEpoch,omitempty"`
+}
+
+type GenesisDelegation struct {
+	DelegateKeyHash        string `json:"delegateKeyHash,omitempty"        dynamodbav:"delegateKeyHash,omitempty"`
+	VerificationKeyHash    string `json:"verificationKeyHash,omitempty"    dynamodbav:"verificationKeyHash,omitempty"`
+	VRFVerificationKeyHash string `json:"vrfVerificationKeyHash,omitempty" dynamodbav:"vrfVerificationKeyHash,omitempty"`
+}
+
+// MoveInstantaneousReward either distributes rewards from the pot to the
+// stake credentials in Rewards, or transfers Value to the other pot
+type MoveInstantaneousReward struct {
+	Pot     string             `json:"pot,omitempty"     dynamodbav:"pot,omitempty"` // reserves or treasury
+	Rewards map[string]num.Int `json:"rewards,omitempty" dynamodbav:"rewards,omitempty"`
+	Value   *num.Int           `json:"value,omitempty"   dynamodbav:"value,omitempty"`
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+const certificatesJSON = `[
+  {"stakeKeyRegistration": "2ce4b3ab4a22a5bbd9cc6b2d4a4a20f4b0e07a49e5d7fa7d6a1e5b8c"},
+  {"stakeKeyDeregistration": "2ce4b3ab4a22a5bbd9cc6b2d4a4a20f4b0e07a49e5d7fa7d6a1e5b8c"},
+  {"stakeDelegation": {"delegator": "2ce4b3ab4a22a5bbd9cc6b2d4a4a20f4b0e07a49e5d7fa7d6a1e5b8c", "delegatee": "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy"}},
+  {"poolRegistration": {
+    "id": "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy",
+    "vrf": "c2b62ffa92ad18ffc117ea3abeb161a68885000a466f9c71db5e4731d6630061",
+    "pledge": 500000000,
+    "cost": 340000000,
+    "margin": "1/20",
+    "rewardAccount": "stake1uxpdrerp9wrxunfh6ukyv5267j70fzxgw0fr3z8zeac5vyqhf9jhy",
+    "owners": ["8a219b698d3b6e034391ae84cee62f1d76b6fbc45ddfe4e31e0d4b60"],
+    "relays": [{"ipv4": "192.168.0.1", "ipv6": null, "port": 3001}, {"hostname": "relay.example.com", "port": null}],
+    "metadata": {"url": "https://example.com/pool.json", "hash": "cc019105f084aef2a956b2f7f2c0bf4e747bf7696705312c244620089429df6f"}
+  }},
+  {"poolRetirement": {"poolId": "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy", "retirementEpoch": 320}},
+  {"genesisDelegation": {"delegateKeyHash": "aa", "verificationKeyHash": "bb", "vrfVerificationKeyHash": "cc"}},
+  {"moveInstantaneousRewards": {"pot": "reserves", "rewards": {"2ce4b3ab4a22a5bbd9cc6b2d4a4a20f4b0e07a49e5d7fa7d6a1e5b8c": -42}}},
+  {"moveInstantaneousRewards": {"pot": "treasury", "value": 1000000}}
+]`
+
+func TestCertificate_JSON(t *testing.T) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(certificatesJSON)))
+	decoder.DisallowUnknownFields()
+
+	var certificates []Certificate
+	err := decoder.Decode(&certificates)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var types []CertificateType
+	for _, c := range certificates {
+		types = append(types, c.Type())
+	}
+	assert.Equal(t, []CertificateType{
+		CertificateTypeStakeKeyRegistration,
+		CertificateTypeStakeKeyDeregistration,
+		CertificateTypeStakeDelegation,
+		CertificateTypePoolRegistration,
+		CertificateTypePoolRetirement,
+		CertificateTypeGenesisDelegation,
+		CertificateTypeMoveInstantaneousReward,
+		CertificateTypeMoveInstantaneousReward,
+	}, types)
+
+	pool := certificates[3].PoolRegistration
+	assert.Equal(t, "340000000", pool.Cost.String())
+	assert.Equal(t, "192.168.0.1", *pool.Relays[0].IPv4)
+	assert.Nil(t, pool.Relays[0].IPv6)
+	assert.Equal(t, uint16(3001), *pool.Relays[0].Port)
+	assert.Equal(t, "relay.example.com", *pool.Relays[1].Hostname)
+	assert.Equal(t, "-42", certificates[6].MoveInstantaneousRewards.Rewards["2ce4b3ab4a22a5bbd9cc6b2d4a4a20f4b0e07a49e5d7fa7d6a1e5b8c"].String())
+	assert.Equal(t, "1000000", certificates[7].MoveInstantaneousRewards.Value.String())
+
+	data, err := json.Marshal(certificates)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var got []Certificate
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, certificates, got)
+}
+
+func TestCertificate_Unknown(t *testing.T) {
+	const data = `[{"futureCertificate":{"id":1}},{"stakeKeyRegistration":"abc"}]`
+
+	var certificates []Certificate
+	if err := json.Unmarshal([]byte(data), &certificates); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, CertificateTypeUnknown, certificates[0].Type())
+	assert.JSONEq(t, `{"futureCertificate":{"id":1}}`, string(certificates[0].Raw))
+	assert.Nil(t, certificates[1].Raw)
+
+	got, err := json.Marshal(certificates)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.JSONEq(t, data, string(got))
+}
+
+func TestCertificate_DynamoDB(t *testing.T) {
+	var want []Certificate
+	if err := json.Unmarshal([]byte(certificatesJSON), &want); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	item, err := dynamodbattribute.Marshal(want)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got []Certificate
+	if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, want, got)
+
+	t.Run("unknown", func(t *testing.T) {
+		want := Certificate{Raw: json.RawMessage(`{"futureCertificate":{"id":1}}`)}
+
+		item, err := dynamodbattribute.Marshal(want)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var got Certificate
+		if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("legacy raw json", func(t *testing.T) {
+		item := &dynamodb.AttributeValue{B: []byte(`{"stakeKeyRegistration":"abc"}`)}
+
+		var got Certificate
+		if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, "abc", got.StakeKeyRegistration)
+	})
+}
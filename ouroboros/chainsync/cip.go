@@ -0,0 +1,284 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/plutusdata"
+)
+
+const (
+	// CIP20Label identifies transaction message metadata
+	CIP20Label = 674
+	// CIP25Label identifies NFT metadata
+	CIP25Label = 721
+)
+
+// NFTMetadata holds the standard fields shared by CIP-25 metadata and CIP-68
+// reference datums; any other fields are available in Properties
+type NFTMetadata struct {
+	Name        string                 `json:"name,omitempty"`
+	Image       string                 `json:"image,omitempty"`
+	MediaType   string                 `json:"mediaType,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Files       []NFTFile              `json:"files,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+type NFTFile struct {
+	Name      string `json:"name,omitempty"`
+	MediaType string `json:"mediaType,omitempty"`
+	Src       string `json:"src,omitempty"`
+}
+
+// CIP20Message returns the lines of the CIP-20 transaction message, if present
+func (m Metadata) CIP20Message() ([]string, bool) {
+	v, ok := m.Label(CIP20Label)
+	if !ok {
+		return nil, false
+	}
+	msg, ok := v.Get("msg")
+	if !ok || msg.Type != MetadatumTypeList {
+		return nil, false
+	}
+
+	var lines []string
+	for _, item := range msg.List {
+		line, ok := item.Text()
+		if !ok {
+			return nil, false
+		}
+		lines = append(lines, line)
+	}
+	return lines, true
+}
+
+// CIP25 returns the CIP-25 NFT metadata keyed by asset id.  Both version 1,
+// utf8 asset names, and version 2, raw byte keys, are supported
+func (m Metadata) CIP25() (map[AssetID]NFTMetadata, error) {
+	v, ok := m.Label(CIP25Label)
+	if !ok {
+		return nil, nil
+	}
+	if v.Type != MetadatumTypeMap {
+		return nil, fmt.Errorf("failed to decode cip-25 metadata: expected map, got %v", v.Type)
+	}
+
+	nfts := map[AssetID]NFTMetadata{}
+	for _, policy := range v.Map {
+		if key, _ := policy.Key.Text(); key == "version" {
+			continue
+		}
+
+		policyID, err := cip25Key(policy.Key, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode cip-25 policy id: %w", err)
+		}
+		if policy.Value.Type != MetadatumTypeMap {
+			return nil, fmt.Errorf("failed to decode cip-25 metadata for policy, %v: expected map", policyID)
+		}
+
+		for _, asset := range policy.Value.Map {
+			assetName, err := cip25Key(asset.Key, false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode cip-25 asset name for policy, %v: %w", policyID, err)
+			}
+
+			assetID := AssetID(policyID)
+			if assetName != "" {
+				assetID = AssetID(policyID + "." + assetName)
+			}
+			nfts[assetID] = decodeCIP25NFT(asset.Value)
+		}
+	}
+	return nfts, nil
+}
+
+// cip25Key returns the hex encoded key; version 1 policy ids are hex strings
+// and version 1 asset names are utf8 strings
+func cip25Key(key Metadatum, isHex bool) (string, error) {
+	switch key.Type {
+	case MetadatumTypeBytes:
+		return hex.EncodeToString(key.Bytes), nil
+	case MetadatumTypeString:
+		if isHex {
+			return key.String, nil
+		}
+		return hex.EncodeToString([]byte(key.String)), nil
+	default:
+		return "", fmt.Errorf("unexpected key type, %v", key.Type)
+	}
+}
+
+func decodeCIP25NFT(v Metadatum) NFTMetadata {
+	var nft NFTMetadata
+	for _, pair := range v.Map {
+		key, ok := pair.Key.Text()
+		if !ok {
+			continue
+		}
+		text, _ := pair.Value.Text()
+		switch key {
+		case "name":
+			nft.Name = text
+		case "image":
+			nft.Image = text
+		case "mediaType":
+			nft.MediaType = text
+		case "description":
+			nft.Description = text
+		case "files":
+			for _, item := range pair.Value.List {
+				var file NFTFile
+				if v, ok := item.Get("name"); ok {
+					file.Name, _ = v.Text()
+				}
+				if v, ok := item.Get("mediaType"); ok {
+					file.MediaType, _ = v.Text()
+				}
+				if v, ok := item.Get("src"); ok {
+					file.Src, _ = v.Text()
+				}
+				nft.Files = append(nft.Files, file)
+			}
+		default:
+			if nft.Properties == nil {
+				nft.Properties = map[string]interface{}{}
+			}
+			nft.Properties[key] = pair.Value.Interface()
+		}
+	}
+	return nft
+}
+
+// CIP68Datum is the inline datum attached to a CIP-68 reference token
+type CIP68Datum struct {
+	// Metadata with byte string keys decoded as utf8.  Values are *big.Int,
+	// string (utf8 bytes), []byte, []interface{}, map[string]interface{} or,
+	// for constructors, plutusdata.Constr
+	Metadata map[string]interface{}
+	Version  int64
+	Extra    plutusdata.Data // optional, may be nil
+}
+
+// NFT returns the standard fields of the datum metadata
+func (c CIP68Datum) NFT() NFTMetadata {
+	var nft NFTMetadata
+	for key, value := range c.Metadata {
+		text, _ := value.(string)
+		switch key {
+		case "name":
+			nft.Name = text
+		case "image":
+			nft.Image = text
+		case "mediaType":
+			nft.MediaType = text
+		case "description":
+			nft.Description = text
+		case "files":
+			items, _ := value.([]interface{})
+			for _, item := range items {
+				m, _ := item.(map[string]interface{})
+				var file NFTFile
+				file.Name, _ = m["name"].(string)
+				file.MediaType, _ = m["mediaType"].(string)
+				file.Src, _ = m["src"].(string)
+				nft.Files = append(nft.Files, file)
+			}
+		default:
+			if nft.Properties == nil {
+				nft.Properties = map[string]interface{}{}
+			}
+			nft.Properties[key] = value
+		}
+	}
+	return nft
+}
+
+// DecodeCIP68Datum decodes a hex encoded CIP-68 reference datum,
+// Constr 0 [metadata, version, extra], with the plutusdata decoder
+func DecodeCIP68Datum(datum string) (CIP68Datum, error) {
+	v, err := plutusdata.DecodeHex(datum)
+	if err != nil {
+		return CIP68Datum{}, fmt.Errorf("failed to decode cip-68 datum: %w", err)
+	}
+
+	constr, ok := v.(plutusdata.Constr)
+	if !ok || constr.Index != 0 {
+		return CIP68Datum{}, fmt.Errorf("failed to decode cip-68 datum: expected constructor 0")
+	}
+	if len(constr.Fields) < 2 {
+		return CIP68Datum{}, fmt.Errorf("failed to decode cip-68 datum: expected at least 2 fields")
+	}
+
+	metadata, ok := cip68Value(constr.Fields[0]).(map[string]interface{})
+	if !ok {
+		return CIP68Datum{}, fmt.Errorf("failed to decode cip-68 datum: expected metadata map")
+	}
+	version, ok := constr.Fields[1].(plutusdata.Integer)
+	if !ok || !version.Int().IsInt64() {
+		return CIP68Datum{}, fmt.Errorf("failed to decode cip-68 datum: expected integer version")
+	}
+
+	var extra plutusdata.Data
+	if len(constr.Fields) > 2 {
+		extra = constr.Fields[2]
+	}
+
+	return CIP68Datum{
+		Metadata: metadata,
+		Version:  version.Int().Int64(),
+		Extra:    extra,
+	}, nil
+}
+
+// cip68Value converts plutus data into the values documented on CIP68Datum
+func cip68Value(v plutusdata.Data) interface{} {
+	switch v := v.(type) {
+	case plutusdata.Bytes:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return []byte(v)
+	case plutusdata.Integer:
+		return v.Int()
+	case plutusdata.List:
+		items := make([]interface{}, 0, len(v.Items))
+		for _, item := range v.Items {
+			items = append(items, cip68Value(item))
+		}
+		return items
+	case plutusdata.Map:
+		m := map[string]interface{}{}
+		for _, pair := range v.Pairs {
+			var key string
+			switch k := pair.Key.(type) {
+			case plutusdata.Bytes:
+				key = string(k)
+			case plutusdata.Integer:
+				key = k.Int().String()
+			default:
+				continue
+			}
+			m[key] = cip68Value(pair.Value)
+		}
+		return m
+	default:
+		return v
+	}
+}
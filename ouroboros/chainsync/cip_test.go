@@ -0,0 +1,154 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/plutusdata"
+)
+
+func TestMetadata_CIP20Message(t *testing.T) {
+	var metadata Metadata
+	err := json.Unmarshal([]byte(`{"674": {"map": [{"k": {"string": "msg"}, "v": {"list": [{"string": "Invoice-No: 1234"}, {"string": "Order-No: 7654"}]}}]}}`), &metadata)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	lines, ok := metadata.CIP20Message()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Invoice-No: 1234", "Order-No: 7654"}, lines)
+
+	_, ok = Metadata{}.CIP20Message()
+	assert.False(t, ok)
+}
+
+func TestMetadata_CIP25(t *testing.T) {
+	const policyID = "d5e6bf0500378d4f0da4e8dde6becec7621cd8cbf5cbb9b87013d4cc"
+
+	t.Run("v1", func(t *testing.T) {
+		var metadata Metadata
+		err := json.Unmarshal([]byte(`{"721": {"map": [
+		  {"k": {"string": "`+policyID+`"}, "v": {"map": [
+		    {"k": {"string": "Spacebud1"}, "v": {"map": [
+		      {"k": {"string": "name"}, "v": {"string": "SpaceBud #1"}},
+		      {"k": {"string": "image"}, "v": {"list": [{"string": "ipfs://Qm"}, {"string": "abc"}]}},
+		      {"k": {"string": "files"}, "v": {"list": [{"map": [
+		        {"k": {"string": "name"}, "v": {"string": "hi-res"}},
+		        {"k": {"string": "mediaType"}, "v": {"string": "image/png"}},
+		        {"k": {"string": "src"}, "v": {"string": "ipfs://def"}}
+		      ]}]}},
+		      {"k": {"string": "traits"}, "v": {"list": [{"string": "star"}]}}
+		    ]}}
+		  ]}},
+		  {"k": {"string": "version"}, "v": {"string": "1.0"}}
+		]}}`), &metadata)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		nfts, err := metadata.CIP25()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		nft, ok := nfts[AssetID(policyID+".537061636562756431")]
+		assert.True(t, ok)
+		assert.Equal(t, "SpaceBud #1", nft.Name)
+		assert.Equal(t, "ipfs://Qmabc", nft.Image)
+		assert.Equal(t, []NFTFile{{Name: "hi-res", MediaType: "image/png", Src: "ipfs://def"}}, nft.Files)
+		assert.Equal(t, []interface{}{"star"}, nft.Properties["traits"])
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		var metadata Metadata
+		err := json.Unmarshal([]byte(`{"721": {"map": [
+		  {"k": {"bytes": "`+policyID+`"}, "v": {"map": [
+		    {"k": {"bytes": "0001"}, "v": {"map": [{"k": {"string": "name"}, "v": {"string": "raw"}}]}}
+		  ]}},
+		  {"k": {"string": "version"}, "v": {"int": 2}}
+		]}}`), &metadata)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		nfts, err := metadata.CIP25()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, map[AssetID]NFTMetadata{
+			AssetID(policyID + ".0001"): {Name: "raw"},
+		}, nfts)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Metadata{"721": {Type: MetadatumTypeString, String: "nope"}}.CIP25()
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeCIP68Datum(t *testing.T) {
+	// Constr 0 [{"name": "Hello", "image": "ipfs://abc", "tags": ["a"], "id": 7}, 1, Constr 0 []]
+	// encoded with an indefinite length field list
+	const datum = "d8799f" +
+		"a4" +
+		"446e616d65" + "4548656c6c6f" +
+		"45696d616765" + "4a697066733a2f2f616263" +
+		"4474616773" + "9f4161ff" +
+		"426964" + "07" +
+		"01" +
+		"d87980" +
+		"ff"
+
+	got, err := DecodeCIP68Datum(datum)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 1, got.Version)
+
+	nft := got.NFT()
+	assert.Equal(t, "Hello", nft.Name)
+	assert.Equal(t, "ipfs://abc", nft.Image)
+	assert.Equal(t, []interface{}{"a"}, nft.Properties["tags"])
+	assert.Equal(t, big.NewInt(7), nft.Properties["id"])
+
+	assert.Equal(t, "d87980", hex.EncodeToString(plutusdata.Encode(got.Extra)))
+
+	t.Run("bignum", func(t *testing.T) {
+		// Constr 0 [{"id": 2^64}, 1]
+		got, err := DecodeCIP68Datum("d8799fa1426964c24901000000000000000001ff")
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		want := new(big.Int).Lsh(big.NewInt(1), 64)
+		assert.Equal(t, want, got.Metadata["id"])
+	})
+
+	t.Run("trailing bytes", func(t *testing.T) {
+		_, err := DecodeCIP68Datum(datum + "00")
+		assert.Error(t, err)
+	})
+
+	_, err = DecodeCIP68Datum("d87a80") // Constr 1 []
+	assert.Error(t, err)
+
+	_, err = DecodeCIP68Datum("d8799f")
+	assert.Error(t, err)
+}
@@ -1,39 +1,101 @@
 package chainsync
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Era identifies a ledger era.  The zero value is an unknown era that sorts
+// before Byron and supports no features
 type Era struct {
-	name string
+	name    string
+	ordinal int
 }
 
 var (
-	Byron   = Era{name: "byron"}
-	Shelley = Era{name: "shelley"}
-	Allegra = Era{name: "allegra"}
-	Mary    = Era{name: "mary"}
-	Alonzo  = Era{name: "alonzo"}
-	Babbage = Era{name: "babbage"}
+	Byron   = Era{name: "byron", ordinal: 1}
+	Shelley = Era{name: "shelley", ordinal: 2}
+	Allegra = Era{name: "allegra", ordinal: 3}
+	Mary    = Era{name: "mary", ordinal: 4}
+	Alonzo  = Era{name: "alonzo", ordinal: 5}
+	Babbage = Era{name: "babbage", ordinal: 6}
+	Conway  = Era{name: "conway", ordinal: 7}
 )
 
-var Eras = [...]Era{Byron, Shelley, Allegra, Mary, Alonzo, Babbage}
+var Eras = [...]Era{Byron, Shelley, Allegra, Mary, Alonzo, Babbage, Conway}
+
+// ParseEra returns the era with the provided name, ignoring case
+func ParseEra(s string) (Era, error) {
+	for _, era := range Eras {
+		if strings.EqualFold(era.name, s) {
+			return era, nil
+		}
+	}
+	return Era{}, fmt.Errorf("unknown era, %v", s)
+}
 
 func (e Era) String() string {
 	return e.name
 }
 
-func (e Era) AlonzoOrGreater() bool {
-	alonzoIdx := -1
-	for idx, era := range Eras {
-		if era == Alonzo {
-			alonzoIdx = idx
-		}
+// Compare returns -1, 0 or +1 depending on whether e is before, the same as or
+// after that
+func (e Era) Compare(that Era) int {
+	switch {
+	case e.ordinal < that.ordinal:
+		return -1
+	case e.ordinal > that.ordinal:
+		return 1
+	default:
+		return 0
 	}
+}
 
-	for idx, era := range Eras {
-		if e == era {
-			return idx >= alonzoIdx
-		}
+// AtLeast returns true if e is a known era no earlier than that
+func (e Era) AtLeast(that Era) bool {
+	return e.ordinal > 0 && e.ordinal >= that.ordinal
+}
+
+func (e Era) AlonzoOrGreater() bool {
+	return e.AtLeast(Alonzo)
+}
+
+func (e Era) SupportsMultiAssets() bool {
+	return e.AtLeast(Mary)
+}
+
+func (e Era) SupportsPlutus() bool {
+	return e.AtLeast(Alonzo)
+}
+
+func (e Era) SupportsInlineDatums() bool {
+	return e.AtLeast(Babbage)
+}
+
+func (e Era) SupportsReferenceInputs() bool {
+	return e.AtLeast(Babbage)
+}
+
+func (e Era) SupportsGovernance() bool {
+	return e.AtLeast(Conway)
+}
+
+func (e Era) MarshalText() ([]byte, error) {
+	return []byte(e.name), nil
+}
+
+func (e *Era) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*e = Era{}
+		return nil
 	}
 
-	panic("new era unaccounted for")
+	era, err := ParseEra(string(data))
+	if err != nil {
+		return err
+	}
+	*e = era
+	return nil
 }
 
 func (r RollForwardBlock) Era() Era {
@@ -50,23 +112,40 @@ func (r RollForwardBlock) Era() Era {
 		return Shelley
 	case r.Babbage != nil:
 		return Babbage
+	case r.Conway != nil:
+		return Conway
 	default:
 		return Era{}
 	}
 }
 
-func (r RollForwardBlock) AlonzoOrGreaterBlock() *Block {
-	if !r.Era().AlonzoOrGreater() {
-		return nil
-	}
-
-	if r.Alonzo != nil {
+// Block returns the block regardless of era.  Byron blocks are converted to
+// the common shape, carrying only their header fields and transaction inputs
+// and outputs
+func (r RollForwardBlock) Block() *Block {
+	switch {
+	case r.Byron != nil:
+		return r.Byron.Block()
+	case r.Allegra != nil:
+		return r.Allegra
+	case r.Alonzo != nil:
 		return r.Alonzo
-	}
-
-	if r.Babbage != nil {
+	case r.Mary != nil:
+		return r.Mary
+	case r.Shelley != nil:
+		return r.Shelley
+	case r.Babbage != nil:
 		return r.Babbage
+	case r.Conway != nil:
+		return r.Conway
+	default:
+		return nil
 	}
+}
 
-	return nil
+func (r RollForwardBlock) AlonzoOrGreaterBlock() *Block {
+	if !r.Era().AlonzoOrGreater() {
+		return nil
+	}
+	return r.Block()
 }
@@ -1,13 +1,15 @@
 package chainsync
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestAlonzoOrGreater(t *testing.T) {
-	expectedResults := []bool{false, false, false, false, true, true}
+	expectedResults := []bool{false, false, false, false, true, true, true}
 	gotResults := make([]bool, 0, len(expectedResults))
 
 	for _, era := range Eras {
@@ -17,3 +19,86 @@ func TestAlonzoOrGreater(t *testing.T) {
 
 	assert.Equal(t, expectedResults, gotResults)
 }
+
+func TestAlonzoOrGreater_Unknown(t *testing.T) {
+	assert.False(t, Era{}.AlonzoOrGreater())
+	assert.False(t, Era{name: "unknown"}.AlonzoOrGreater())
+}
+
+func TestEra_Compare(t *testing.T) {
+	for i, a := range Eras {
+		for j, b := range Eras {
+			switch {
+			case i < j:
+				assert.Equal(t, -1, a.Compare(b))
+				assert.False(t, a.AtLeast(b))
+			case i > j:
+				assert.Equal(t, 1, a.Compare(b))
+				assert.True(t, a.AtLeast(b))
+			default:
+				assert.Equal(t, 0, a.Compare(b))
+				assert.True(t, a.AtLeast(b))
+			}
+		}
+	}
+
+	assert.Equal(t, -1, Era{}.Compare(Byron))
+	assert.False(t, Era{}.AtLeast(Era{}))
+}
+
+func TestEra_Features(t *testing.T) {
+	assert.False(t, Allegra.SupportsMultiAssets())
+	assert.True(t, Mary.SupportsMultiAssets())
+	assert.False(t, Mary.SupportsPlutus())
+	assert.True(t, Alonzo.SupportsPlutus())
+	assert.False(t, Alonzo.SupportsInlineDatums())
+	assert.True(t, Babbage.SupportsInlineDatums())
+	assert.True(t, Babbage.SupportsReferenceInputs())
+	assert.False(t, Babbage.SupportsGovernance())
+	assert.True(t, Conway.SupportsGovernance())
+}
+
+func TestParseEra(t *testing.T) {
+	for _, era := range Eras {
+		got, err := ParseEra(strings.ToUpper(era.String()))
+		assert.NoError(t, err)
+		assert.Equal(t, era, got)
+	}
+
+	_, err := ParseEra("goguen")
+	assert.Error(t, err)
+}
+
+func TestEra_JSON(t *testing.T) {
+	type Envelope struct {
+		Era Era `json:"era"`
+	}
+
+	data, err := json.Marshal(Envelope{Era: Babbage})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"era":"babbage"}`, string(data))
+
+	var got Envelope
+	err = json.Unmarshal(data, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, Babbage, got.Era)
+
+	err = json.Unmarshal([]byte(`{"era":"goguen"}`), &got)
+	assert.Error(t, err)
+}
+
+func TestRollForwardBlock_Block(t *testing.T) {
+	block := &Block{HeaderHash: "abc"}
+	assert.Equal(t, block, RollForwardBlock{Mary: block}.Block())
+	assert.Nil(t, RollForwardBlock{}.Block())
+	assert.Nil(t, RollForwardBlock{Mary: block}.AlonzoOrGreaterBlock())
+	assert.Equal(t, block, RollForwardBlock{Babbage: block}.AlonzoOrGreaterBlock())
+
+	byron := RollForwardBlock{Byron: &ByronBlock{
+		Hash:   "def",
+		Header: ByronHeader{BlockHeight: 1, Slot: 2},
+		Body:   ByronBody{TxPayload: []ByronTxPayload{{ID: "tx"}}},
+	}}
+	assert.Equal(t, "tx", byron.Block().Body[0].ID)
+	assert.Equal(t, PointStruct{BlockNo: 1, Hash: "def", Slot: 2}, byron.PointStruct())
+}
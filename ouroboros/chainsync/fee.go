@@ -0,0 +1,322 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/cbor"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/address"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+const (
+	// utxoEntryOverhead is the per output overhead, in bytes, added to the
+	// serialized size of an output from babbage onwards
+	utxoEntryOverhead = 160
+	// utxoEntrySizeWithoutVal is the per output overhead, in words, prior to
+	// babbage
+	utxoEntrySizeWithoutVal = 27
+	// adaOnlyValueSize is the size, in words, of a value without assets
+	adaOnlyValueSize = 2
+	// dataHashSize is the size, in words, of a datum hash
+	dataHashSize = 10
+
+	// reference scripts are priced in tiers of refScriptTierSize bytes, each
+	// tier costing refScriptTierMultiplier times the previous
+	refScriptTierSize = 25600
+)
+
+var refScriptTierMultiplier = big.NewRat(6, 5)
+
+// MinUtxo returns the minimum lovelace required by out. Babbage onwards this
+// depends on the serialized size of the output, and so the coins in out are
+// raised as needed until the output itself holds the minimum
+func (p ProtocolParameters) MinUtxo(out TxOut) (num.Int, error) {
+	switch {
+	case p.CoinsPerUtxoByte != nil:
+		coins := out.Value.Coins
+		for i := 0; i < 8; i++ {
+			out.Value.Coins = coins
+			size, err := txOutSize(out)
+			if err != nil {
+				return num.Int{}, fmt.Errorf("failed to compute min utxo: %w", err)
+			}
+			minUtxo := num.Uint64(utxoEntryOverhead + size).Mul(*p.CoinsPerUtxoByte)
+			if coins.Cmp(minUtxo) >= 0 {
+				return minUtxo, nil
+			}
+			coins = minUtxo
+		}
+		return num.Int{}, fmt.Errorf("failed to compute min utxo: did not converge")
+
+	case p.CoinsPerUtxoWord != nil:
+		words := utxoEntrySizeWithoutVal + valueSizeInWords(out.Value)
+		if out.DatumHash != "" {
+			words += dataHashSize
+		}
+		return num.Uint64(words).Mul(*p.CoinsPerUtxoWord), nil
+
+	case p.MinUtxoValue != nil:
+		if len(out.Value.NativeAssets().Assets) == 0 {
+			return *p.MinUtxoValue, nil
+		}
+		perWord := p.MinUtxoValue.Quo(num.Uint64(utxoEntrySizeWithoutVal))
+		scaled := num.Uint64(utxoEntrySizeWithoutVal + valueSizeInWords(out.Value)).Mul(perWord)
+		if scaled.Cmp(*p.MinUtxoValue) < 0 {
+			return *p.MinUtxoValue, nil
+		}
+		return scaled, nil
+
+	default:
+		return num.Int{}, fmt.Errorf("failed to compute min utxo: protocol parameters define no min utxo parameter")
+	}
+}
+
+// ScriptFee returns the cost of the provided execution units, rounded up
+func (p ProtocolParameters) ScriptFee(units ExecutionUnits) (num.Int, error) {
+	if units.Memory == 0 && units.Steps == 0 {
+		return num.Int{}, nil
+	}
+	if p.Prices == nil {
+		return num.Int{}, fmt.Errorf("failed to compute script fee: protocol parameters define no prices")
+	}
+
+	cost := new(big.Rat).Mul(p.Prices.Memory.Rat(), new(big.Rat).SetInt(new(big.Int).SetUint64(units.Memory)))
+	cost.Add(cost, new(big.Rat).Mul(p.Prices.Steps.Rat(), new(big.Rat).SetInt(new(big.Int).SetUint64(units.Steps))))
+	return num.Ceil(cost), nil
+}
+
+// ReferenceScriptFee returns the conway fee for size bytes of reference
+// scripts; each successive 25KiB tier is 1.2 times the price of the last
+func (p ProtocolParameters) ReferenceScriptFee(size uint64) (num.Int, error) {
+	if size == 0 {
+		return num.Int{}, nil
+	}
+	if p.MinFeeReferenceScripts == nil {
+		return num.Int{}, fmt.Errorf("failed to compute reference script fee: protocol parameters define no minFeeReferenceScripts")
+	}
+
+	var (
+		total = new(big.Rat)
+		price = p.MinFeeReferenceScripts.Rat()
+	)
+	for size > 0 {
+		n := size
+		if n > refScriptTierSize {
+			n = refScriptTierSize
+		}
+		total.Add(total, new(big.Rat).Mul(price, new(big.Rat).SetUint64(n)))
+		price.Mul(price, refScriptTierMultiplier)
+		size -= n
+	}
+	return num.Floor(total), nil
+}
+
+// MinFee returns the minimum fee for a transaction of txSize bytes that
+// consumes the provided execution units and spends or references refScriptSize
+// bytes of reference scripts
+func (p ProtocolParameters) MinFee(txSize uint64, units ExecutionUnits, refScriptSize uint64) (num.Int, error) {
+	if p.MinFeeCoefficient == nil || p.MinFeeConstant == nil {
+		return num.Int{}, fmt.Errorf("failed to compute min fee: protocol parameters define no fee coefficients")
+	}
+
+	fee := num.Uint64(*p.MinFeeCoefficient).Mul(num.Uint64(txSize)).Add(num.Uint64(*p.MinFeeConstant))
+
+	scriptFee, err := p.ScriptFee(units)
+	if err != nil {
+		return num.Int{}, fmt.Errorf("failed to compute min fee: %w", err)
+	}
+	refScriptFee, err := p.ReferenceScriptFee(refScriptSize)
+	if err != nil {
+		return num.Int{}, fmt.Errorf("failed to compute min fee: %w", err)
+	}
+
+	return fee.Add(scriptFee).Add(refScriptFee), nil
+}
+
+// MinFee returns the minimum fee for tx using the size of its raw serialized
+// form and the execution units of its redeemers
+func (t Tx) MinFee(p ProtocolParameters, refScriptSize uint64) (num.Int, error) {
+	if t.Raw == "" {
+		return num.Int{}, fmt.Errorf("failed to compute min fee for tx, %v: raw transaction not available", t.ID)
+	}
+	raw, err := base64.StdEncoding.DecodeString(t.Raw)
+	if err != nil {
+		return num.Int{}, fmt.Errorf("failed to compute min fee for tx, %v: %w", t.ID, err)
+	}
+	return p.MinFee(uint64(len(raw)), t.ExecutionUnits(), refScriptSize)
+}
+
+// ExecutionUnits returns the total execution units of the tx redeemers
+func (t Tx) ExecutionUnits() ExecutionUnits {
+	var total ExecutionUnits
+	for _, redeemer := range t.Witness.Redeemers {
+		total.Memory += redeemer.ExecutionUnits.Memory
+		total.Steps += redeemer.ExecutionUnits.Steps
+	}
+	return total
+}
+
+// valueSizeInWords is the pre-babbage size of a value, in 8 byte words
+func valueSizeInWords(v Value) uint64 {
+	assetIDs := v.AssetIDs()
+	if len(assetIDs) == 0 {
+		return adaOnlyValueSize
+	}
+
+	var nameBytes uint64
+	for _, assetID := range assetIDs {
+		nameBytes += uint64(len(assetID.AssetName()) / 2)
+	}
+	bytes := uint64(len(assetIDs))*12 + nameBytes + uint64(len(v.PolicyIDs()))*PolicyIDSize
+	return 6 + (bytes+7)/8
+}
+
+// txOutSize returns the size of the cbor encoding of out. Outputs without an
+// inline datum or reference script use the compact legacy array encoding
+func txOutSize(out TxOut) (uint64, error) {
+	addr, err := address.Parse(out.Address)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := valueSize(out.Value)
+	if err != nil {
+		return 0, err
+	}
+	size := cborBytesSize(uint64(len(addr.Bytes()))) + value
+
+	if out.Datum == "" && out.Script == nil {
+		fields := uint64(2)
+		if out.DatumHash != "" {
+			fields++
+			size += cborBytesSize(uint64(len(out.DatumHash) / 2))
+		}
+		return cbor.HeadSize(fields) + size, nil
+	}
+
+	fields := uint64(2)
+	size += 2 // map keys 0 and 1
+
+	switch {
+	case out.Datum != "":
+		fields++
+		// [1, #6.24(bytes .cbor plutus_data)]
+		size += 1 + cbor.HeadSize(2) + 1 + cbor.HeadSize(24) + cborBytesSize(uint64(len(out.Datum)/2))
+	case out.DatumHash != "":
+		// [0, datum_hash]
+		fields++
+		size += 1 + cbor.HeadSize(2) + 1 + cborBytesSize(uint64(len(out.DatumHash)/2))
+	}
+
+	if out.Script != nil {
+		script, err := scriptSize(*out.Script)
+		if err != nil {
+			return 0, err
+		}
+		// #6.24(bytes .cbor script)
+		fields++
+		size += 1 + cbor.HeadSize(24) + cborBytesSize(script)
+	}
+
+	return cbor.HeadSize(fields) + size, nil
+}
+
+func valueSize(v Value) (uint64, error) {
+	coins, err := v.Coins.CheckedUint64()
+	if err != nil {
+		return 0, err
+	}
+
+	assetIDs := v.AssetIDs()
+	if len(assetIDs) == 0 {
+		return cbor.HeadSize(coins), nil
+	}
+
+	policies := map[string][]AssetID{}
+	for _, assetID := range assetIDs {
+		policies[assetID.PolicyID()] = append(policies[assetID.PolicyID()], assetID)
+	}
+
+	size := cbor.HeadSize(2) + cbor.HeadSize(coins) + cbor.HeadSize(uint64(len(policies)))
+	for policyID, assets := range policies {
+		size += cborBytesSize(uint64(len(policyID)/2)) + cbor.HeadSize(uint64(len(assets)))
+		for _, assetID := range assets {
+			quantity, err := v.Assets[assetID].CheckedUint64()
+			if err != nil {
+				return 0, fmt.Errorf("invalid quantity for asset, %v: %w", assetID, err)
+			}
+			size += cborBytesSize(uint64(len(assetID.AssetName())/2)) + cbor.HeadSize(quantity)
+		}
+	}
+	return size, nil
+}
+
+// scriptSize returns the size of the tagged script, [type, script]
+func scriptSize(s Script) (uint64, error) {
+	if s.Native != nil {
+		size, err := nativeScriptSize(*s.Native)
+		if err != nil {
+			return 0, err
+		}
+		return cbor.HeadSize(2) + 1 + size, nil
+	}
+
+	version, code, err := s.Plutus()
+	if err != nil {
+		return 0, err
+	}
+	if version == 0 {
+		return 0, fmt.Errorf("empty script")
+	}
+	return cbor.HeadSize(2) + 1 + cborBytesSize(uint64(len(code))), nil
+}
+
+func nativeScriptSize(n NativeScript) (uint64, error) {
+	scripts := func() (uint64, error) {
+		size := cbor.HeadSize(uint64(len(n.Scripts)))
+		for _, script := range n.Scripts {
+			v, err := nativeScriptSize(script)
+			if err != nil {
+				return 0, err
+			}
+			size += v
+		}
+		return size, nil
+	}
+
+	switch n.Type {
+	case NativeScriptTypeSignature:
+		return cbor.HeadSize(2) + 1 + cborBytesSize(uint64(len(n.KeyHash)/2)), nil
+	case NativeScriptTypeAll, NativeScriptTypeAny:
+		size, err := scripts()
+		return cbor.HeadSize(2) + 1 + size, err
+	case NativeScriptTypeNOf:
+		size, err := scripts()
+		return cbor.HeadSize(3) + 1 + cbor.HeadSize(uint64(n.N)) + size, err
+	case NativeScriptTypeStartsAt, NativeScriptTypeExpiresAt:
+		return cbor.HeadSize(2) + 1 + cbor.HeadSize(n.Slot), nil
+	default:
+		return 0, fmt.Errorf("unknown native script type, %v", n.Type)
+	}
+}
+
+// cborBytesSize returns the size of a byte string of length n
+func cborBytesSize(n uint64) uint64 {
+	return cbor.HeadSize(n) + n
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+const (
+	testBaseAddress       = "addr1qx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3n0d3vllmyqwsx5wktcd8cc3sq835lu7drv2xwl2wywfgse35a3x"
+	testEnterpriseAddress = "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"
+	testPolicyID          = "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209"
+)
+
+func ptrInt(v int64) *num.Int {
+	i := num.Int64(v)
+	return &i
+}
+
+func TestProtocolParameters_MinUtxo(t *testing.T) {
+	var (
+		babbage = ProtocolParameters{CoinsPerUtxoByte: ptrInt(4310)}
+		alonzo  = ProtocolParameters{CoinsPerUtxoWord: ptrInt(34482)}
+		mary    = ProtocolParameters{MinUtxoValue: ptrInt(1000000)}
+	)
+
+	longName := AssetID(testPolicyID + "." + "0102030405060708091011121314151617181920212223242526272829303132")
+
+	tests := map[string]struct {
+		params ProtocolParameters
+		out    TxOut
+		want   int64
+	}{
+		"babbage base": {
+			params: babbage,
+			out:    TxOut{Address: testBaseAddress},
+			want:   969750,
+		},
+		"babbage enterprise": {
+			params: babbage,
+			out:    TxOut{Address: testEnterpriseAddress, Value: Value{Coins: num.Int64(5000000)}},
+			want:   849070,
+		},
+		"babbage datum hash": {
+			params: babbage,
+			out: TxOut{
+				Address:   testBaseAddress,
+				DatumHash: "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec",
+			},
+			want: (160 + 1 + 59 + 5 + 34) * 4310,
+		},
+		"babbage inline datum and script": {
+			params: babbage,
+			out: TxOut{
+				Address: testBaseAddress,
+				Datum:   "d87980",
+				Script:  &Script{PlutusV2: "Tk0BAAAzIiIgBRIAEgAR"},
+			},
+			// map(4) + 0:addr + 1:coin + 2:[1, 24(h'd87980')] + 3:24(h'[2, h'...'])
+			want: (160 + 1 + (1 + 59) + (1 + 5) + (1 + 1 + 1 + 2 + 4) + (1 + 2 + 1 + (1 + 1 + 16))) * 4310,
+		},
+		"babbage inline datum and hex script": {
+			params: babbage,
+			out: TxOut{
+				Address: testBaseAddress,
+				Datum:   "d87980",
+				Script:  &Script{PlutusV2: "4e4d01000033222220051200120011"},
+			},
+			want: (160 + 1 + (1 + 59) + (1 + 5) + (1 + 1 + 1 + 2 + 4) + (1 + 2 + 1 + (1 + 1 + 16))) * 4310,
+		},
+		"babbage assets": {
+			params: babbage,
+			out: TxOut{
+				Address: testBaseAddress,
+				Value:   Value{Assets: map[AssetID]num.Int{longName: num.Int64(1)}},
+			},
+			// [coin, {h'policy': {h'name': 1}}]
+			want: (160 + 1 + 59 + (1 + 5 + 1 + 30 + 1 + 34 + 1)) * 4310,
+		},
+		"alonzo ada only": {
+			params: alonzo,
+			out:    TxOut{Address: testBaseAddress},
+			want:   999978,
+		},
+		"alonzo datum hash": {
+			params: alonzo,
+			out:    TxOut{Address: testBaseAddress, DatumHash: "00"},
+			want:   (27 + 2 + 10) * 34482,
+		},
+		"mary ada only": {
+			params: mary,
+			out:    TxOut{Address: testBaseAddress},
+			want:   1000000,
+		},
+		"mary assets": {
+			params: mary,
+			out: TxOut{
+				Address: testBaseAddress,
+				Value:   Value{Assets: map[AssetID]num.Int{longName: num.Int64(1)}},
+			},
+			want: 1555554,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.params.MinUtxo(tc.out)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, num.Int64(tc.want).String(), got.String())
+		})
+	}
+
+	_, err := ProtocolParameters{}.MinUtxo(TxOut{Address: testBaseAddress})
+	assert.NotNil(t, err)
+}
+
+func TestProtocolParameters_MinFee(t *testing.T) {
+	var (
+		a      = uint64(44)
+		b      = uint64(155381)
+		prices = Prices{Memory: NewRatio(577, 10000), Steps: NewRatio(721, 10000000)}
+		ref    = NewRatio(15, 1)
+		params = ProtocolParameters{
+			MinFeeCoefficient:      &a,
+			MinFeeConstant:         &b,
+			Prices:                 &prices,
+			MinFeeReferenceScripts: &ref,
+		}
+	)
+
+	fee, err := params.MinFee(300, ExecutionUnits{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "168581", fee.String())
+
+	fee, err = params.ScriptFee(ExecutionUnits{Memory: 1000000, Steps: 500000000})
+	assert.Nil(t, err)
+	assert.Equal(t, "93750", fee.String())
+
+	fee, err = params.ScriptFee(ExecutionUnits{Memory: 1, Steps: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "1", fee.String()) // rounded up
+
+	fee, err = params.ReferenceScriptFee(30000)
+	assert.Nil(t, err)
+	assert.Equal(t, "463200", fee.String()) // 25600*15 + 4400*18
+
+	fee, err = params.MinFee(300, ExecutionUnits{Memory: 1000000, Steps: 500000000}, 30000)
+	assert.Nil(t, err)
+	assert.Equal(t, "725531", fee.String())
+
+	tx := Tx{
+		Raw: base64.StdEncoding.EncodeToString(make([]byte, 300)),
+		Witness: Witness{Redeemers: Redeemers{
+			"spend:0": {ExecutionUnits: ExecutionUnits{Memory: 400000, Steps: 200000000}},
+			"mint:0":  {ExecutionUnits: ExecutionUnits{Memory: 600000, Steps: 300000000}},
+		}},
+	}
+	fee, err = tx.MinFee(params, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "262331", fee.String())
+
+	_, err = ProtocolParameters{}.MinFee(300, ExecutionUnits{}, 0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,287 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// Anchor references off chain content by url and hash
+type Anchor struct {
+	URL  string `json:"url"  dynamodbav:"url"`
+	Hash string `json:"hash" dynamodbav:"hash"`
+}
+
+// CredentialOrigin distinguishes key from script credentials
+type CredentialOrigin string
+
+const (
+	CredentialOriginVerificationKey CredentialOrigin = "verificationKey"
+	CredentialOriginScript          CredentialOrigin = "script"
+)
+
+type DRepType string
+
+const (
+	DRepTypeKey          DRepType = "key"
+	DRepTypeScript       DRepType = "script"
+	DRepTypeAbstain      DRepType = "abstain"
+	DRepTypeNoConfidence DRepType = "noConfidence"
+)
+
+// DRep identifies a delegate representative; ID is only set for the key and
+// script types
+type DRep struct {
+	Type DRepType `json:"type"         dynamodbav:"type"`
+	ID   string   `json:"id,omitempty" dynamodbav:"id,omitempty"`
+}
+
+// GovActionID identifies a governance action by the transaction that proposed
+// it and its index within that transaction's proposals
+type GovActionID struct {
+	TxHash string `json:"txId"  dynamodbav:"txId"`
+	Index  int    `json:"index" dynamodbav:"index"`
+}
+
+// ProposalProcedure submits a governance action
+type ProposalProcedure struct {
+	Deposit       num.Int   `json:"deposit"       dynamodbav:"deposit"`
+	RewardAccount string    `json:"rewardAccount" dynamodbav:"rewardAccount"`
+	Action        GovAction `json:"action"        dynamodbav:"action"`
+	Anchor        Anchor    `json:"anchor"        dynamodbav:"anchor"`
+}
+
+type GovActionType string
+
+const (
+	GovActionTypeUnknown             GovActionType = ""
+	GovActionTypeParameterChange     GovActionType = "parameterChange"
+	GovActionTypeHardForkInitiation  GovActionType = "hardForkInitiation"
+	GovActionTypeTreasuryWithdrawals GovActionType = "treasuryWithdrawals"
+	GovActionTypeNoConfidence        GovActionType = "noConfidence"
+	GovActionTypeUpdateCommittee     GovActionType = "updateCommittee"
+	GovActionTypeNewConstitution     GovActionType = "newConstitution"
+	GovActionTypeInfo                GovActionType = "info"
+)
+
+// GovAction is a tagged union; exactly one field will be set
+type GovAction struct {
+	ParameterChange     *ParameterChangeAction     `json:"parameterChange,omitempty"     dynamodbav:"parameterChange,omitempty"`
+	HardForkInitiation  *HardForkInitiationAction  `json:"hardForkInitiation,omitempty"  dynamodbav:"hardForkInitiation,omitempty"`
+	TreasuryWithdrawals *TreasuryWithdrawalsAction `json:"treasuryWithdrawals,omitempty" dynamodbav:"treasuryWithdrawals,omitempty"`
+	NoConfidence        *NoConfidenceAction        `json:"noConfidence,omitempty"        dynamodbav:"noConfidence,omitempty"`
+	UpdateCommittee     *UpdateCommitteeAction     `json:"updateCommittee,omitempty"     dynamodbav:"updateCommittee,omitempty"`
+	NewConstitution     *NewConstitutionAction     `json:"newConstitution,omitempty"     dynamodbav:"newConstitution,omitempty"`
+	Info                *InfoAction                `json:"info,omitempty"                dynamodbav:"info,omitempty"`
+}
+
+// Type returns the kind of governance action
+func (g GovAction) Type() GovActionType {
+	switch {
+	case g.ParameterChange != nil:
+		return GovActionTypeParameterChange
+	case g.HardForkInitiation != nil:
+		return GovActionTypeHardForkInitiation
+	case g.TreasuryWithdrawals != nil:
+		return GovActionTypeTreasuryWithdrawals
+	case g.NoConfidence != nil:
+		return GovActionTypeNoConfidence
+	case g.UpdateCommittee != nil:
+		return GovActionTypeUpdateCommittee
+	case g.NewConstitution != nil:
+		return GovActionTypeNewConstitution
+	case g.Info != nil:
+		return GovActionTypeInfo
+	default:
+		return GovActionTypeUnknown
+	}
+}
+
+type ParameterChangeAction struct {
+	PreviousAction       *GovActionID       `json:"previousAction,omitempty"       dynamodbav:"previousAction,omitempty"`
+	Parameters           ProtocolParameters `json:"parameters"                     dynamodbav:"parameters"`
+	GuardrailsScriptHash string             `json:"guardrailsScriptHash,omitempty" dynamodbav:"guardrailsScriptHash,omitempty"`
+}
+
+type HardForkInitiationAction struct {
+	PreviousAction  *GovActionID    `json:"previousAction,omitempty" dynamodbav:"previousAction,omitempty"`
+	ProtocolVersion ProtocolVersion `json:"protocolVersion"          dynamodbav:"protocolVersion"`
+}
+
+type TreasuryWithdrawalsAction struct {
+	Withdrawals          map[string]num.Int `json:"withdrawals"                    dynamodbav:"withdrawals"` // reward account to lovelace
+	GuardrailsScriptHash string             `json:"guardrailsScriptHash,omitempty" dynamodbav:"guardrailsScriptHash,omitempty"`
+}
+
+type NoConfidenceAction struct {
+	PreviousAction *GovActionID `json:"previousAction,omitempty" dynamodbav:"previousAction,omitempty"`
+}
+
+type UpdateCommitteeAction struct {
+	PreviousAction *GovActionID      `json:"previousAction,omitempty" dynamodbav:"previousAction,omitempty"`
+	Remove         []string          `json:"remove,omitempty"         dynamodbav:"remove,omitempty"` // cold credentials
+	Add            map[string]uint64 `json:"add,omitempty"            dynamodbav:"add,omitempty"`    // cold credential to expiry epoch
+	Quorum         Ratio             `json:"quorum"                   dynamodbav:"quorum"`
+}
+
+type NewConstitutionAction struct {
+	PreviousAction *GovActionID `json:"previousAction,omitempty" dynamodbav:"previousAction,omitempty"`
+	Constitution   Constitution `json:"constitution"             dynamodbav:"constitution"`
+}
+
+type Constitution struct {
+	Anchor               Anchor `json:"anchor"                         dynamodbav:"anchor"`
+	GuardrailsScriptHash string `json:"guardrailsScriptHash,omitempty" dynamodbav:"guardrailsScriptHash,omitempty"`
+}
+
+// InfoAction has no effect on chain; it exists only to be voted on
+type InfoAction struct{}
+
+// MarshalDynamoDBAttributeValue stores a marker as dynamodb omits empty maps
+func (InfoAction) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	item.BOOL = aws.Bool(true)
+	return nil
+}
+
+func (*InfoAction) UnmarshalDynamoDBAttributeValue(*dynamodb.AttributeValue) error {
+	return nil
+}
+
+type VoterRole string
+
+const (
+	VoterRoleConstitutionalCommittee VoterRole = "constitutionalCommittee"
+	VoterRoleDelegateRepresentative  VoterRole = "delegateRepresentative"
+	VoterRoleStakePoolOperator       VoterRole = "stakePoolOperator"
+)
+
+type Voter struct {
+	Role VoterRole        `json:"role"           dynamodbav:"role"`
+	ID   string           `json:"id"             dynamodbav:"id"`
+	From CredentialOrigin `json:"from,omitempty" dynamodbav:"from,omitempty"` // stake pool operators are always keys
+}
+
+type Vote string
+
+const (
+	VoteYes     Vote = "yes"
+	VoteNo      Vote = "no"
+	VoteAbstain Vote = "abstain"
+)
+
+// VotingProcedure records a single voter's vote on a governance action
+type VotingProcedure struct {
+	Voter    Voter       `json:"voter"            dynamodbav:"voter"`
+	Proposal GovActionID `json:"proposal"         dynamodbav:"proposal"`
+	Vote     Vote        `json:"vote"             dynamodbav:"vote"`
+	Anchor   *Anchor     `json:"anchor,omitempty" dynamodbav:"anchor,omitempty"`
+}
+
+// SortedVoters returns the distinct voters in ledger order; committee members,
+// then delegate representatives, then stake pool operators.  Within a role,
+// script credentials precede key credentials, then voters are ordered by id
+func (t TxBody) SortedVoters() []Voter {
+	rank := map[VoterRole]int{
+		VoterRoleConstitutionalCommittee: 0,
+		VoterRoleDelegateRepresentative:  1,
+		VoterRoleStakePoolOperator:       2,
+	}
+
+	seen := map[Voter]struct{}{}
+	var voters []Voter
+	for _, v := range t.Votes {
+		if _, ok := seen[v.Voter]; ok {
+			continue
+		}
+		seen[v.Voter] = struct{}{}
+		voters = append(voters, v.Voter)
+	}
+
+	sort.Slice(voters, func(i, j int) bool {
+		a, b := voters[i], voters[j]
+		switch {
+		case a.Role != b.Role:
+			return rank[a.Role] < rank[b.Role]
+		case a.From != b.From:
+			return a.From == CredentialOriginScript
+		default:
+			return a.ID < b.ID
+		}
+	})
+	return voters
+}
+
+// DRepRegistration is used by drep registration, deregistration and update
+// certificates; Deposit is unset for updates and Anchor for deregistrations
+type DRepRegistration struct {
+	Credential string   `json:"credential"        dynamodbav:"credential"`
+	Deposit    *num.Int `json:"deposit,omitempty" dynamodbav:"deposit,omitempty"`
+	Anchor     *Anchor  `json:"anchor,omitempty"  dynamodbav:"anchor,omitempty"`
+}
+
+// StakeCredentialDeposit registers or deregisters a stake credential with an
+// explicit deposit
+type StakeCredentialDeposit struct {
+	Credential string  `json:"credential" dynamodbav:"credential"`
+	Deposit    num.Int `json:"deposit"    dynamodbav:"deposit"`
+}
+
+// Delegation covers the conway delegation certificates; Pool is set when
+// delegating stake, DRep when delegating votes and Deposit when the
+// certificate also registers the stake credential
+type Delegation struct {
+	Delegator string   `json:"delegator"         dynamodbav:"delegator"`
+	Pool      string   `json:"pool,omitempty"    dynamodbav:"pool,omitempty"`
+	DRep      *DRep    `json:"drep,omitempty"    dynamodbav:"drep,omitempty"`
+	Deposit   *num.Int `json:"deposit,omitempty" dynamodbav:"deposit,omitempty"`
+}
+
+type CommitteeAuthorization struct {
+	ColdCredential string `json:"coldCredential" dynamodbav:"coldCredential"`
+	HotCredential  string `json:"hotCredential"  dynamodbav:"hotCredential"`
+}
+
+type CommitteeResignation struct {
+	ColdCredential string  `json:"coldCredential"   dynamodbav:"coldCredential"`
+	Anchor         *Anchor `json:"anchor,omitempty" dynamodbav:"anchor,omitempty"`
+}
+
+// PoolVotingThresholds are the fractions of active stake that must vote yes
+type PoolVotingThresholds struct {
+	MotionNoConfidence    Ratio `json:"motionNoConfidence"    dynamodbav:"motionNoConfidence"`
+	CommitteeNormal       Ratio `json:"committeeNormal"       dynamodbav:"committeeNormal"`
+	CommitteeNoConfidence Ratio `json:"committeeNoConfidence" dynamodbav:"committeeNoConfidence"`
+	HardForkInitiation    Ratio `json:"hardForkInitiation"    dynamodbav:"hardForkInitiation"`
+	PPSecurityGroup       Ratio `json:"ppSecurityGroup"       dynamodbav:"ppSecurityGroup"`
+}
+
+// DRepVotingThresholds are the fractions of delegated stake that must vote yes
+type DRepVotingThresholds struct {
+	MotionNoConfidence    Ratio `json:"motionNoConfidence"    dynamodbav:"motionNoConfidence"`
+	CommitteeNormal       Ratio `json:"committeeNormal"       dynamodbav:"committeeNormal"`
+	CommitteeNoConfidence Ratio `json:"committeeNoConfidence" dynamodbav:"committeeNoConfidence"`
+	UpdateConstitution    Ratio `json:"updateConstitution"    dynamodbav:"updateConstitution"`
+	HardForkInitiation    Ratio `json:"hardForkInitiation"    dynamodbav:"hardForkInitiation"`
+	PPNetworkGroup        Ratio `json:"ppNetworkGroup"        dynamodbav:"ppNetworkGroup"`
+	PPEconomicGroup       Ratio `json:"ppEconomicGroup"       dynamodbav:"ppEconomicGroup"`
+	PPTechnicalGroup      Ratio `json:"ppTechnicalGroup"      dynamodbav:"ppTechnicalGroup"`
+	PPGovGroup            Ratio `json:"ppGovGroup"            dynamodbav:"ppGovGroup"`
+	TreasuryWithdrawal    Ratio `json:"treasuryWithdrawal"    dynamodbav:"treasuryWithdrawal"`
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+const conwayBlockJSON = `{
+  "conway": {
+    "headerHash": "2d2c2b2a",
+    "header": {"blockHeight": 100, "slot": 200},
+    "body": [{
+      "id": "aa01",
+      "body": {
+        "inputs": [{"txId": "bb02", "index": 0}],
+        "certificates": [
+          {"stakeCredentialRegistration": {"credential": "c1", "deposit": 2000000}},
+          {"voteDelegation": {"delegator": "c1", "drep": {"type": "abstain"}}},
+          {"stakeVoteRegistrationDelegation": {"delegator": "c2", "pool": "pool1", "drep": {"type": "key", "id": "d1"}, "deposit": 2000000}},
+          {"committeeHotKeyAuthorization": {"coldCredential": "cold", "hotCredential": "hot"}},
+          {"committeeColdKeyResignation": {"coldCredential": "cold", "anchor": {"url": "https://example.com", "hash": "ff"}}},
+          {"drepRegistration": {"credential": "d1", "deposit": 500000000, "anchor": {"url": "https://example.com/drep.json", "hash": "ee"}}},
+          {"drepDeregistration": {"credential": "d1", "deposit": 500000000}},
+          {"drepUpdate": {"credential": "d1"}}
+        ],
+        "proposals": [
+          {
+            "deposit": 100000000000,
+            "rewardAccount": "stake1",
+            "action": {"parameterChange": {"parameters": {"drepDeposit": 600000000, "drepVotingThresholds": {
+              "motionNoConfidence": "67/100", "committeeNormal": "67/100", "committeeNoConfidence": "3/5",
+              "updateConstitution": "3/4", "hardForkInitiation": "3/5", "ppNetworkGroup": "67/100",
+              "ppEconomicGroup": "67/100", "ppTechnicalGroup": "67/100", "ppGovGroup": "3/4", "treasuryWithdrawal": "67/100"
+            }}}},
+            "anchor": {"url": "https://example.com/1", "hash": "01"}
+          },
+          {
+            "deposit": 100000000000,
+            "rewardAccount": "stake1",
+            "action": {"hardForkInitiation": {"previousAction": {"txId": "cc03", "index": 1}, "protocolVersion": {"major": 10, "minor": 0}}},
+            "anchor": {"url": "https://example.com/2", "hash": "02"}
+          },
+          {
+            "deposit": 100000000000,
+            "rewardAccount": "stake1",
+            "action": {"treasuryWithdrawals": {"withdrawals": {"stake2": 42}}},
+            "anchor": {"url": "https://example.com/3", "hash": "03"}
+          },
+          {
+            "deposit": 100000000000,
+            "rewardAccount": "stake1",
+            "action": {"updateCommittee": {"remove": ["cold"], "add": {"cold2": 500}, "quorum": "2/3"}},
+            "anchor": {"url": "https://example.com/4", "hash": "04"}
+          },
+          {
+            "deposit": 100000000000,
+            "rewardAccount": "stake1",
+            "action": {"info": {}},
+            "anchor": {"url": "https://example.com/5", "hash": "05"}
+          }
+        ],
+        "votes": [
+          {"voter": {"role": "stakePoolOperator", "id": "p1"}, "proposal": {"txId": "cc03", "index": 0}, "vote": "no"},
+          {"voter": {"role": "delegateRepresentative", "id": "d2", "from": "verificationKey"}, "proposal": {"txId": "cc03", "index": 0}, "vote": "yes"},
+          {"voter": {"role": "delegateRepresentative", "id": "d9", "from": "script"}, "proposal": {"txId": "cc03", "index": 0}, "vote": "abstain"},
+          {"voter": {"role": "constitutionalCommittee", "id": "hot", "from": "verificationKey"}, "proposal": {"txId": "cc03", "index": 0}, "vote": "yes", "anchor": {"url": "https://example.com/r", "hash": "06"}}
+        ],
+        "treasuryValue": 1000000000000,
+        "donation": 5000000
+      },
+      "witness": {
+        "scripts": {"e1": {"plutus:v3": "Tk0BAAAzIiIgBRIAEgAR"}},
+        "redeemers": {
+          "vote:1": {"redeemer": "2HmA", "executionUnits": {"memory": 1, "steps": 2}},
+          "propose:4": {"redeemer": "2HmA", "executionUnits": {"memory": 1, "steps": 2}}
+        }
+      }
+    }]
+  }
+}`
+
+func TestConwayBlock(t *testing.T) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(conwayBlockJSON)))
+	decoder.DisallowUnknownFields()
+
+	var block RollForwardBlock
+	err := decoder.Decode(&block)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	assert.Equal(t, Conway, block.Era())
+	assert.Equal(t, block.Conway, block.AlonzoOrGreaterBlock())
+	assert.Equal(t, PointStruct{BlockNo: 100, Hash: "2d2c2b2a", Slot: 200}, block.PointStruct())
+
+	tx := block.Conway.Body[0]
+
+	var types []CertificateType
+	for _, c := range tx.Body.Certificates {
+		types = append(types, c.Type())
+	}
+	assert.Equal(t, []CertificateType{
+		CertificateTypeStakeCredentialRegistration,
+		CertificateTypeVoteDelegation,
+		CertificateTypeStakeVoteRegistrationDelegation,
+		CertificateTypeCommitteeHotKeyAuthorization,
+		CertificateTypeCommitteeColdKeyResignation,
+		CertificateTypeDRepRegistration,
+		CertificateTypeDRepDeregistration,
+		CertificateTypeDRepUpdate,
+	}, types)
+	assert.Equal(t, DRepTypeAbstain, tx.Body.Certificates[1].VoteDelegation.DRep.Type)
+	assert.Equal(t, "2000000", tx.Body.Certificates[2].StakeVoteRegistrationDelegation.Deposit.String())
+
+	var actions []GovActionType
+	for _, p := range tx.Body.Proposals {
+		actions = append(actions, p.Action.Type())
+	}
+	assert.Equal(t, []GovActionType{
+		GovActionTypeParameterChange,
+		GovActionTypeHardForkInitiation,
+		GovActionTypeTreasuryWithdrawals,
+		GovActionTypeUpdateCommittee,
+		GovActionTypeInfo,
+	}, actions)
+
+	params := tx.Body.Proposals[0].Action.ParameterChange.Parameters
+	assert.Equal(t, "600000000", params.DRepDeposit.String())
+	assert.Equal(t, "3/4", params.DRepVotingThresholds.PPGovGroup.String())
+	assert.EqualValues(t, 10, tx.Body.Proposals[1].Action.HardForkInitiation.ProtocolVersion.Major)
+	assert.Equal(t, "2/3", tx.Body.Proposals[3].Action.UpdateCommittee.Quorum.String())
+
+	assert.Len(t, tx.Body.Votes, 4)
+	assert.Equal(t, "1000000000000", tx.Body.TreasuryValue.String())
+	assert.Equal(t, "5000000", tx.Body.Donation.String())
+	assert.Equal(t, "Tk0BAAAzIiIgBRIAEgAR", tx.Witness.Scripts["e1"].PlutusV3)
+
+	targets, err := tx.RedeemerTargets()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, &Voter{Role: VoterRoleDelegateRepresentative, ID: "d9", From: CredentialOriginScript}, targets["vote:1"].Voter)
+	assert.Equal(t, GovActionTypeInfo, targets["propose:4"].Proposal.Action.Type())
+
+	item, err := dynamodbattribute.Marshal(tx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got Tx
+	err = dynamodbattribute.Unmarshal(item, &got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, tx.Body.Certificates, got.Body.Certificates)
+	assert.Equal(t, tx.Body.Proposals, got.Body.Proposals)
+	assert.Equal(t, tx.Body.Votes, got.Body.Votes)
+}
+
+func TestTxBody_SortedVoters(t *testing.T) {
+	body := TxBody{
+		Votes: []VotingProcedure{
+			{Voter: Voter{Role: VoterRoleStakePoolOperator, ID: "a"}},
+			{Voter: Voter{Role: VoterRoleDelegateRepresentative, ID: "b", From: CredentialOriginVerificationKey}},
+			{Voter: Voter{Role: VoterRoleDelegateRepresentative, ID: "c", From: CredentialOriginScript}},
+			{Voter: Voter{Role: VoterRoleDelegateRepresentative, ID: "a", From: CredentialOriginVerificationKey}},
+			{Voter: Voter{Role: VoterRoleConstitutionalCommittee, ID: "z", From: CredentialOriginVerificationKey}},
+			{Voter: Voter{Role: VoterRoleStakePoolOperator, ID: "a"}},
+		},
+	}
+
+	var got []string
+	for _, v := range body.SortedVoters() {
+		got = append(got, string(v.Role)+":"+v.ID)
+	}
+	assert.Equal(t, []string{
+		"constitutionalCommittee:z",
+		"delegateRepresentative:c",
+		"delegateRepresentative:a",
+		"delegateRepresentative:b",
+		"stakePoolOperator:a",
+	}, got)
+}
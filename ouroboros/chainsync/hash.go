@@ -0,0 +1,189 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/plutusdata"
+)
+
+// ErrHashMismatch is returned, wrapped, when a recomputed hash differs from
+// the one reported
+var ErrHashMismatch = errors.New("hash mismatch")
+
+// HashTxBody returns the transaction id, the hex encoded blake2b-256 hash, of
+// the cbor encoded transaction body
+func HashTxBody(body []byte) string {
+	sum := blake2b.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeTxID returns the id of a cbor encoded transaction e.g. the signed
+// transaction passed to SubmitTx
+func ComputeTxID(tx []byte) (string, error) {
+	body, err := txBody(tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute tx id: %w", err)
+	}
+	return HashTxBody(body), nil
+}
+
+// ComputeID returns the id of the transaction recomputed from Raw
+func (t Tx) ComputeID() (string, error) {
+	body, err := t.RawBody()
+	if err != nil {
+		return "", err
+	}
+	return HashTxBody(body), nil
+}
+
+// VerifyID returns an error wrapping ErrHashMismatch if ID is not the hash of
+// the body held by Raw
+func (t Tx) VerifyID() error {
+	id, err := t.ComputeID()
+	if err != nil {
+		return err
+	}
+	if id != t.ID {
+		return fmt.Errorf("tx id, %v, does not match raw body hash, %v: %w", t.ID, id, ErrHashMismatch)
+	}
+	return nil
+}
+
+// MarshalCBOR encodes the script as the ledger does
+func (n NativeScript) MarshalCBOR() ([]byte, error) {
+	v, err := n.cbor()
+	if err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(v)
+}
+
+func (n NativeScript) cbor() ([]interface{}, error) {
+	scripts := func() ([]interface{}, error) {
+		items := make([]interface{}, 0, len(n.Scripts))
+		for _, s := range n.Scripts {
+			item, err := s.cbor()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	switch n.Type {
+	case NativeScriptTypeSignature:
+		keyHash, err := hex.DecodeString(n.KeyHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode native script: invalid key hash, %v", n.KeyHash)
+		}
+		return []interface{}{0, keyHash}, nil
+	case NativeScriptTypeAll, NativeScriptTypeAny:
+		items, err := scripts()
+		if err != nil {
+			return nil, err
+		}
+		kind := 1
+		if n.Type == NativeScriptTypeAny {
+			kind = 2
+		}
+		return []interface{}{kind, items}, nil
+	case NativeScriptTypeNOf:
+		items, err := scripts()
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{3, n.N, items}, nil
+	case NativeScriptTypeStartsAt:
+		return []interface{}{4, n.Slot}, nil
+	case NativeScriptTypeExpiresAt:
+		return []interface{}{5, n.Slot}, nil
+	default:
+		return nil, fmt.Errorf("failed to encode native script: unknown type, %v", n.Type)
+	}
+}
+
+// Hash returns the hex encoded script hash.  The hash is computed over the
+// canonical encoding, which matches the ledger for scripts built by the
+// standard tooling
+func (n NativeScript) Hash() (string, error) {
+	data, err := n.MarshalCBOR()
+	if err != nil {
+		return "", err
+	}
+	return scriptHash(scriptPrefixNative, data), nil
+}
+
+// Hash returns the hex encoded blake2b-224 script hash, which is also the
+// policy id of assets minted by the script
+func (s Script) Hash() (string, error) {
+	if s.Native != nil {
+		return s.Native.Hash()
+	}
+
+	version, code, err := s.Plutus()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash script: %w", err)
+	}
+	if version == 0 {
+		return "", fmt.Errorf("failed to hash script: no script set")
+	}
+	return scriptHash(uint64(version), code), nil
+}
+
+// PolicyID returns the policy id of assets minted by the script
+func (s Script) PolicyID() (string, error) {
+	return s.Hash()
+}
+
+// HashDatum returns the datum hash of hex encoded plutus data e.g. an inline
+// TxOut.Datum
+func HashDatum(datum string) (string, error) {
+	data, err := hex.DecodeString(datum)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash datum: %w", err)
+	}
+	return plutusdata.Hash(data), nil
+}
+
+// VerifyHashes checks that the datums and scripts are keyed by their hashes
+func (w Witness) VerifyHashes() error {
+	for hash, datum := range w.Datums {
+		got, err := HashDatum(datum)
+		if err != nil {
+			return err
+		}
+		if got != hash {
+			return fmt.Errorf("datum keyed by %v hashes to %v: %w", hash, got, ErrHashMismatch)
+		}
+	}
+	for hash, script := range w.Scripts {
+		got, err := script.Hash()
+		if err != nil {
+			return err
+		}
+		if got != hash {
+			return fmt.Errorf("script keyed by %v hashes to %v: %w", hash, got, ErrHashMismatch)
+		}
+	}
+	return nil
+}
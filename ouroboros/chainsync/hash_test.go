@@ -0,0 +1,180 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/cbor"
+)
+
+func TestComputeTxID(t *testing.T) {
+	// [{0: [], 1: [], 2: 0}, {}, true, null]
+	raw := mustHex(t, "84a3008001800200a0f5f6")
+	id, err := ComputeTxID(raw)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, HashTxBody(mustHex(t, "a3008001800200")), id)
+
+	tx, err := DecodeTx(raw)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, id, tx.ID)
+	assert.Nil(t, tx.VerifyID())
+
+	tx.ID = "abc"
+	if err := tx.VerifyID(); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("got %v; want %v", err, ErrHashMismatch)
+	}
+
+	_, err = ComputeTxID(mustHex(t, "a0"))
+	assert.NotNil(t, err)
+}
+
+func TestScript_Hash(t *testing.T) {
+	const keyHash = "9499315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+
+	t.Run("plutus", func(t *testing.T) {
+		// the always succeeds script distributed with cardano-node
+		script := Script{PlutusV1: "TQEAADMiIiAFEgASABE="}
+		got, err := script.Hash()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, "67f33146617a5e61936081db3b2117cbf59bd2123748f58ac9678656", got)
+
+		policyID, err := script.PolicyID()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, got, policyID)
+	})
+
+	t.Run("plutus hex", func(t *testing.T) {
+		// ogmios >= 5.5 emits hex encoded scripts
+		script := Script{PlutusV1: "4d01000033222220051200120011"}
+		got, err := script.PolicyID()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, "67f33146617a5e61936081db3b2117cbf59bd2123748f58ac9678656", got)
+
+		witness := Witness{Scripts: Scripts{got: script}}
+		assert.Nil(t, witness.VerifyHashes())
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		_, err := Script{PlutusV1: "not base64"}.Hash()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("native", func(t *testing.T) {
+		native := NativeScript{
+			Type: NativeScriptTypeAll,
+			Scripts: []NativeScript{
+				{Type: NativeScriptTypeSignature, KeyHash: keyHash},
+				{Type: NativeScriptTypeExpiresAt, Slot: 1000},
+				{Type: NativeScriptTypeNOf, N: 1, Scripts: []NativeScript{{Type: NativeScriptTypeStartsAt, Slot: 10}}},
+			},
+		}
+		data, err := native.MarshalCBOR()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, "8201838200581c"+keyHash+"82051903e88303018182040a", hex.EncodeToString(data))
+
+		// the hash must agree with the one computed when decoding raw scripts
+		_, want, err := decodeScript(cbor.NewReader(data), scriptPrefixNative)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		got, err := Script{Native: &native}.Hash()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Script{}.Hash()
+		assert.NotNil(t, err)
+
+		_, err = Script{Native: &NativeScript{Type: "bogus"}}.Hash()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestWitness_VerifyHashes(t *testing.T) {
+	datum := "d8799f4568656c6c6fff"
+	datumHash, err := HashDatum(datum)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	witness := Witness{
+		Datums:  Datums{datumHash: datum},
+		Scripts: Scripts{"67f33146617a5e61936081db3b2117cbf59bd2123748f58ac9678656": {PlutusV1: "TQEAADMiIiAFEgASABE="}},
+	}
+	assert.Nil(t, witness.VerifyHashes())
+
+	witness.Datums = Datums{"abc": datum}
+	if err := witness.VerifyHashes(); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("got %v; want %v", err, ErrHashMismatch)
+	}
+}
+
+func TestWitness_VerifyHashesDynamoDB(t *testing.T) {
+	data, err := os.ReadFile("testdata/scoop.json")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var item map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var tx Tx
+	if err := dynamodbattribute.Unmarshal(item["tx"], &tx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	for _, hash := range []string{
+		"4020e7fc2de75a0729c3cc3af715b34d98381e0cdbcfa99c950bc3ac",
+		"ba158766c1bae60e2117ee8987621441fac66a5e0fb9c7aca58cf20a",
+	} {
+		got, err := tx.Witness.Scripts[hash].Hash()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, hash, got)
+	}
+	assert.Nil(t, tx.Witness.VerifyHashes())
+
+	tx.Witness.Scripts = Scripts{"abc": tx.Witness.Scripts["4020e7fc2de75a0729c3cc3af715b34d98381e0cdbcfa99c950bc3ac"]}
+	if err := tx.Witness.VerifyHashes(); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("got %v; want %v", err, ErrHashMismatch)
+	}
+}
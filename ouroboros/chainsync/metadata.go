@@ -0,0 +1,228 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// AuxiliaryData holds the transaction metadata and any auxiliary scripts
+type AuxiliaryData struct {
+	Hash string             `json:"hash,omitempty"`
+	Body *AuxiliaryDataBody `json:"body,omitempty"`
+}
+
+type AuxiliaryDataBody struct {
+	Blob    Metadata `json:"blob,omitempty"`
+	Scripts []Script `json:"scripts,omitempty"`
+}
+
+// Metadata returns the metadata blob; nil if the transaction has none
+func (a *AuxiliaryData) Metadata() Metadata {
+	if a == nil || a.Body == nil {
+		return nil
+	}
+	return a.Body.Blob
+}
+
+// MarshalDynamoDBAttributeValue stores auxiliary data as raw json, the format
+// used before metadata was typed
+func (a AuxiliaryData) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to marshal auxiliary data: %w", err)
+	}
+	item.B = data
+	return nil
+}
+
+func (a *AuxiliaryData) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil || len(item.B) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(item.B, a); err != nil {
+		return fmt.Errorf("failed to unmarshal auxiliary data: %w", err)
+	}
+	return nil
+}
+
+// Metadata maps metadata labels, as decimal strings, to values
+type Metadata map[string]Metadatum
+
+// Label returns the value for the provided label
+func (m Metadata) Label(label uint64) (Metadatum, bool) {
+	v, ok := m[strconv.FormatUint(label, 10)]
+	return v, ok
+}
+
+type MetadatumType string
+
+const (
+	MetadatumTypeInt    MetadatumType = "int"
+	MetadatumTypeString MetadatumType = "string"
+	MetadatumTypeBytes  MetadatumType = "bytes"
+	MetadatumTypeList   MetadatumType = "list"
+	MetadatumTypeMap    MetadatumType = "map"
+)
+
+// Metadatum is a single metadata value; the field matching Type is set
+type Metadatum struct {
+	Type   MetadatumType
+	Int    num.Int
+	String string
+	Bytes  []byte
+	List   []Metadatum
+	Map    []MetadatumPair
+}
+
+type MetadatumPair struct {
+	Key   Metadatum `json:"k"`
+	Value Metadatum `json:"v"`
+}
+
+// Get returns the value of the map entry whose key is the provided string
+func (m Metadatum) Get(key string) (Metadatum, bool) {
+	for _, pair := range m.Map {
+		if k, ok := pair.Key.Text(); ok && k == key {
+			return pair.Value, true
+		}
+	}
+	return Metadatum{}, false
+}
+
+// Text returns the metadatum as a string.  Strings are returned as is, bytes
+// are interpreted as utf8 and lists of strings, used to work around the 64
+// byte limit on metadata strings, are concatenated
+func (m Metadatum) Text() (string, bool) {
+	switch m.Type {
+	case MetadatumTypeString:
+		return m.String, true
+	case MetadatumTypeBytes:
+		return string(m.Bytes), true
+	case MetadatumTypeList:
+		var s string
+		for _, item := range m.List {
+			v, ok := item.Text()
+			if !ok {
+				return "", false
+			}
+			s += v
+		}
+		return s, true
+	default:
+		return "", false
+	}
+}
+
+// Interface converts the metadatum into plain go values; ints become
+// *big.Int, bytes []byte, lists []interface{} and maps map[string]interface{}
+// when every key is text, otherwise []MetadatumPair
+func (m Metadatum) Interface() interface{} {
+	switch m.Type {
+	case MetadatumTypeInt:
+		return m.Int.BigInt()
+	case MetadatumTypeString:
+		return m.String
+	case MetadatumTypeBytes:
+		return m.Bytes
+	case MetadatumTypeList:
+		items := make([]interface{}, 0, len(m.List))
+		for _, item := range m.List {
+			items = append(items, item.Interface())
+		}
+		return items
+	case MetadatumTypeMap:
+		values := map[string]interface{}{}
+		for _, pair := range m.Map {
+			if pair.Key.Type != MetadatumTypeString {
+				return m.Map
+			}
+			values[pair.Key.String] = pair.Value.Interface()
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func (m Metadatum) MarshalJSON() ([]byte, error) {
+	switch m.Type {
+	case MetadatumTypeInt:
+		return json.Marshal(map[string]num.Int{"int": m.Int})
+	case MetadatumTypeString:
+		return json.Marshal(map[string]string{"string": m.String})
+	case MetadatumTypeBytes:
+		return json.Marshal(map[string]string{"bytes": hex.EncodeToString(m.Bytes)})
+	case MetadatumTypeList:
+		list := m.List
+		if list == nil {
+			list = []Metadatum{}
+		}
+		return json.Marshal(map[string][]Metadatum{"list": list})
+	case MetadatumTypeMap:
+		pairs := m.Map
+		if pairs == nil {
+			pairs = []MetadatumPair{}
+		}
+		return json.Marshal(map[string][]MetadatumPair{"map": pairs})
+	default:
+		return nil, fmt.Errorf("unable to marshal metadatum: unknown type, %v", m.Type)
+	}
+}
+
+func (m *Metadatum) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal metadatum: %w", err)
+	}
+	if len(raw) != 1 {
+		return fmt.Errorf("failed to unmarshal metadatum: expected exactly one key, %v", string(data))
+	}
+
+	for key, value := range raw {
+		v := Metadatum{Type: MetadatumType(key)}
+		var err error
+		switch v.Type {
+		case MetadatumTypeInt:
+			err = json.Unmarshal(value, &v.Int)
+		case MetadatumTypeString:
+			err = json.Unmarshal(value, &v.String)
+		case MetadatumTypeBytes:
+			var s string
+			if err = json.Unmarshal(value, &s); err == nil {
+				v.Bytes, err = hex.DecodeString(s)
+			}
+		case MetadatumTypeList:
+			err = json.Unmarshal(value, &v.List)
+		case MetadatumTypeMap:
+			err = json.Unmarshal(value, &v.Map)
+		default:
+			return fmt.Errorf("failed to unmarshal metadatum: unknown type, %v", key)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal metadatum, %v: %w", key, err)
+		}
+		*m = v
+	}
+
+	return nil
+}
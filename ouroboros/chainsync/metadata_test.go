@@ -0,0 +1,114 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+const auxiliaryDataJSON = `{
+  "hash": "b1c2",
+  "body": {
+    "blob": {
+      "674": {"map": [{"k": {"string": "msg"}, "v": {"list": [{"string": "hello"}, {"string": "world"}]}}]},
+      "42": {"list": [{"int": -1}, {"int": 123456789012345678901234567890}, {"bytes": "cafe"}]}
+    },
+    "scripts": [{"native": "2ce4b3ab4a22a5bbd9cc6b2d4a4a20f4b0e07a49e5d7fa7d6a1e5b8c"}]
+  }
+}`
+
+func TestAuxiliaryData_JSON(t *testing.T) {
+	var aux AuxiliaryData
+	err := json.Unmarshal([]byte(auxiliaryDataJSON), &aux)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	v, ok := aux.Metadata().Label(42)
+	assert.True(t, ok)
+	assert.Equal(t, MetadatumTypeList, v.Type)
+	assert.Len(t, v.List, 3)
+	assert.Equal(t, "-1", v.List[0].Int.String())
+	assert.Equal(t, "123456789012345678901234567890", v.List[1].Int.String())
+	assert.Equal(t, []byte{0xca, 0xfe}, v.List[2].Bytes)
+	assert.Len(t, aux.Body.Scripts, 1)
+
+	data, err := json.Marshal(aux)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got AuxiliaryData
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, aux, got)
+
+	_, ok = (*AuxiliaryData)(nil).Metadata().Label(42)
+	assert.False(t, ok)
+}
+
+func TestAuxiliaryData_DynamoDB(t *testing.T) {
+	var aux AuxiliaryData
+	err := json.Unmarshal([]byte(auxiliaryDataJSON), &aux)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	tx := Tx{ID: "abc", Metadata: &aux}
+	item, err := dynamodbattribute.Marshal(tx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got Tx
+	err = dynamodbattribute.Unmarshal(item, &got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, tx, got)
+}
+
+func TestMetadatum(t *testing.T) {
+	var v Metadatum
+	err := json.Unmarshal([]byte(`{"map": [{"k": {"int": 1}, "v": {"string": "one"}}, {"k": {"string": "two"}, "v": {"list": [{"string": "a"}, {"bytes": "62"}]}}]}`), &v)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	two, ok := v.Get("two")
+	assert.True(t, ok)
+	text, ok := two.Text()
+	assert.True(t, ok)
+	assert.Equal(t, "ab", text)
+
+	_, ok = v.Get("three")
+	assert.False(t, ok)
+
+	// non-text keys cannot be represented as map[string]interface{}
+	_, ok = v.Interface().([]MetadatumPair)
+	assert.True(t, ok)
+
+	err = json.Unmarshal([]byte(`{"int": 1, "string": "a"}`), &v)
+	assert.Error(t, err)
+
+	err = json.Unmarshal([]byte(`{"float": 1.5}`), &v)
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrScriptNotSatisfied is returned, wrapped, when a native script evaluates
+// to false
+var ErrScriptNotSatisfied = errors.New("native script not satisfied")
+
+// Evaluate checks the script as the ledger would for a transaction signed by
+// the provided key hashes and valid for the interval.  A nil error means the
+// script is satisfied; otherwise the error, wrapping ErrScriptNotSatisfied,
+// describes why.
+//
+// Zero bounds are treated as unset since ValidityInterval can't tell an
+// explicit slot 0 from a missing bound.  This diverges from the ledger in one
+// case: a StartsAt 0 script in a tx with an explicit lower bound of 0 is
+// satisfied on chain but reported unsatisfied here
+func (n NativeScript) Evaluate(signers []string, interval ValidityInterval) error {
+	keyHashes := make(map[string]struct{}, len(signers))
+	for _, s := range signers {
+		keyHashes[strings.ToLower(s)] = struct{}{}
+	}
+
+	if reason := n.evaluate(keyHashes, interval); reason != "" {
+		return fmt.Errorf("%w: %v", ErrScriptNotSatisfied, reason)
+	}
+	return nil
+}
+
+// evaluate returns the reason the script is not satisfied or the empty string
+func (n NativeScript) evaluate(signers map[string]struct{}, interval ValidityInterval) string {
+	switch n.Type {
+	case NativeScriptTypeSignature:
+		if _, ok := signers[strings.ToLower(n.KeyHash)]; !ok {
+			return fmt.Sprintf("missing signature from %v", n.KeyHash)
+		}
+		return ""
+
+	case NativeScriptTypeStartsAt:
+		if interval.InvalidBefore == 0 {
+			return fmt.Sprintf("starts at slot %v but the tx has no lower bound", n.Slot)
+		}
+		if interval.InvalidBefore < n.Slot {
+			return fmt.Sprintf("starts at slot %v but the tx is valid from slot %v", n.Slot, interval.InvalidBefore)
+		}
+		return ""
+
+	case NativeScriptTypeExpiresAt:
+		if interval.InvalidHereafter == 0 {
+			return fmt.Sprintf("expires at slot %v but the tx has no upper bound", n.Slot)
+		}
+		if interval.InvalidHereafter > n.Slot {
+			return fmt.Sprintf("expires at slot %v but the tx is valid until slot %v", n.Slot, interval.InvalidHereafter)
+		}
+		return ""
+
+	case NativeScriptTypeAll:
+		for _, s := range n.Scripts {
+			if reason := s.evaluate(signers, interval); reason != "" {
+				return "all: " + reason
+			}
+		}
+		return ""
+
+	case NativeScriptTypeAny:
+		return n.evaluateAtLeast(1, signers, interval)
+
+	case NativeScriptTypeNOf:
+		return n.evaluateAtLeast(n.N, signers, interval)
+
+	default:
+		return fmt.Sprintf("unknown script type, %v", n.Type)
+	}
+}
+
+func (n NativeScript) evaluateAtLeast(required int, signers map[string]struct{}, interval ValidityInterval) string {
+	var (
+		satisfied int
+		reasons   []string
+	)
+	for _, s := range n.Scripts {
+		if satisfied >= required {
+			return ""
+		}
+		if reason := s.evaluate(signers, interval); reason != "" {
+			reasons = append(reasons, reason)
+			continue
+		}
+		satisfied++
+	}
+	if satisfied >= required {
+		return ""
+	}
+	return fmt.Sprintf("%v: %v of %v required satisfied [%v]", n.Type, satisfied, required, strings.Join(reasons, "; "))
+}
+
+// EvaluateNativeScript evaluates the script against the verified signers and
+// validity interval of the transaction; see Signers
+func (t Tx) EvaluateNativeScript(n NativeScript, opts ...SignatureOption) error {
+	signers, err := t.Signers(opts...)
+	if err != nil {
+		return err
+	}
+	return n.Evaluate(signers, t.Body.ValidityInterval)
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestNativeScript_Evaluate(t *testing.T) {
+	const (
+		alice = "9499315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+		bob   = "337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251"
+		carol = "2a748e3885f6f73320ad16a8331247b81fe01b8d39f57eec9caa5091"
+	)
+	var (
+		sig = func(keyHash string) NativeScript {
+			return NativeScript{Type: NativeScriptTypeSignature, KeyHash: keyHash}
+		}
+		twoOfThree = NativeScript{Type: NativeScriptTypeNOf, N: 2, Scripts: []NativeScript{sig(alice), sig(bob), sig(carol)}}
+		timelocked = NativeScript{Type: NativeScriptTypeAll, Scripts: []NativeScript{
+			sig(alice),
+			{Type: NativeScriptTypeStartsAt, Slot: 100},
+			{Type: NativeScriptTypeExpiresAt, Slot: 200},
+		}}
+	)
+
+	testCases := map[string]struct {
+		script   NativeScript
+		signers  []string
+		interval ValidityInterval
+		want     bool
+	}{
+		"sig": {
+			script:  sig(alice),
+			signers: []string{alice},
+			want:    true,
+		},
+		"sig - missing": {
+			script:  sig(alice),
+			signers: []string{bob},
+		},
+		"n of - satisfied": {
+			script:  twoOfThree,
+			signers: []string{alice, carol},
+			want:    true,
+		},
+		"n of - insufficient": {
+			script:  twoOfThree,
+			signers: []string{bob},
+		},
+		"any - empty": {
+			script: NativeScript{Type: NativeScriptTypeAny},
+		},
+		"all - empty": {
+			script: NativeScript{Type: NativeScriptTypeAll},
+			want:   true,
+		},
+		"timelock - within": {
+			script:   timelocked,
+			signers:  []string{alice},
+			interval: ValidityInterval{InvalidBefore: 100, InvalidHereafter: 200},
+			want:     true,
+		},
+		"timelock - too early": {
+			script:   timelocked,
+			signers:  []string{alice},
+			interval: ValidityInterval{InvalidBefore: 99, InvalidHereafter: 200},
+		},
+		"timelock - too late": {
+			script:   timelocked,
+			signers:  []string{alice},
+			interval: ValidityInterval{InvalidBefore: 100, InvalidHereafter: 201},
+		},
+		"timelock - unbounded": {
+			script:  timelocked,
+			signers: []string{alice},
+		},
+		"starts at - zero lower bound": {
+			// the ledger accepts an explicit lower bound of 0, which can't be
+			// told apart from an unset bound
+			script: NativeScript{Type: NativeScriptTypeStartsAt},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			err := tc.script.Evaluate(tc.signers, tc.interval)
+			if tc.want {
+				if err != nil {
+					t.Fatalf("got %v; want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrScriptNotSatisfied) {
+				t.Fatalf("got %v; want %v", err, ErrScriptNotSatisfied)
+			}
+		})
+	}
+}
+
+func TestTx_EvaluateNativeScript(t *testing.T) {
+	data, err := os.ReadFile("testdata/scoop.json")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var item map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var tx Tx
+	if err := dynamodbattribute.Unmarshal(item["tx"], &tx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	keyHash, err := HashKey("88bc0a6abb1ffafebd8e5bb8efe11675149f8bd421d7e9ed2cb0af8cd3881f1a")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := tx.EvaluateNativeScript(NativeScript{Type: NativeScriptTypeSignature, KeyHash: keyHash}, WithTrustedID()); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	other := NativeScript{Type: NativeScriptTypeSignature, KeyHash: "9499315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"}
+	if err := tx.EvaluateNativeScript(other, WithTrustedID()); !errors.Is(err, ErrScriptNotSatisfied) {
+		t.Fatalf("got %v; want %v", err, ErrScriptNotSatisfied)
+	}
+}
@@ -15,11 +15,14 @@
 package num
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/fxamacker/cbor/v2"
 )
 
 type Int big.Int
@@ -53,18 +56,126 @@ func (i Int) BigInt() *big.Int {
 	return &bi
 }
 
+// Int returns the value as an int; the result is undefined on overflow, see
+// CheckedInt
 func (i Int) Int() int {
 	return int(i.BigInt().Int64())
 }
 
+// Int64 returns the value as an int64; the result is undefined on overflow,
+// see CheckedInt64
 func (i Int) Int64() int64 {
 	return i.BigInt().Int64()
 }
 
+// Uint64 returns the value as a uint64; the result is undefined on overflow
+// or for negative values, see CheckedUint64
 func (i Int) Uint64() uint64 {
 	return i.BigInt().Uint64()
 }
 
+// CheckedInt returns the value as an int or an error if it does not fit
+func (i Int) CheckedInt() (int, error) {
+	v, err := i.CheckedInt64()
+	if err != nil {
+		return 0, err
+	}
+	if v < math.MinInt || v > math.MaxInt {
+		return 0, fmt.Errorf("%v overflows int", i)
+	}
+	return int(v), nil
+}
+
+// CheckedInt64 returns the value as an int64 or an error if it does not fit
+func (i Int) CheckedInt64() (int64, error) {
+	if bi := i.BigInt(); !bi.IsInt64() {
+		return 0, fmt.Errorf("%v overflows int64", bi)
+	}
+	return i.BigInt().Int64(), nil
+}
+
+// CheckedUint64 returns the value as a uint64 or an error if it is negative
+// or does not fit
+func (i Int) CheckedUint64() (uint64, error) {
+	if bi := i.BigInt(); !bi.IsUint64() {
+		return 0, fmt.Errorf("%v overflows uint64", bi)
+	}
+	return i.BigInt().Uint64(), nil
+}
+
+func (i Int) Cmp(that Int) int {
+	return i.BigInt().Cmp(that.BigInt())
+}
+
+func (i Int) Sign() int {
+	return i.BigInt().Sign()
+}
+
+func (i Int) IsZero() bool {
+	return i.Sign() == 0
+}
+
+func (i Int) Neg() Int {
+	v := big.NewInt(0).Neg(i.BigInt())
+	return Int(*v)
+}
+
+func (i Int) Abs() Int {
+	v := big.NewInt(0).Abs(i.BigInt())
+	return Int(*v)
+}
+
+func (i Int) Mul(that Int) Int {
+	product := big.NewInt(0).Mul(i.BigInt(), that.BigInt())
+	return Int(*product)
+}
+
+// Quo returns i / that truncated towards zero; panics if that is zero
+func (i Int) Quo(that Int) Int {
+	quotient := big.NewInt(0).Quo(i.BigInt(), that.BigInt())
+	return Int(*quotient)
+}
+
+// Rem returns the remainder of Quo
+func (i Int) Rem(that Int) Int {
+	remainder := big.NewInt(0).Rem(i.BigInt(), that.BigInt())
+	return Int(*remainder)
+}
+
+// Rat returns the value as an exact rational
+func (i Int) Rat() *big.Rat {
+	return new(big.Rat).SetInt(i.BigInt())
+}
+
+// MulRatFloor returns i * r rounded towards negative infinity
+func (i Int) MulRatFloor(r *big.Rat) Int {
+	return Floor(new(big.Rat).Mul(i.Rat(), r))
+}
+
+// MulRatCeil returns i * r rounded towards positive infinity, as the ledger
+// does when computing fees and deposits
+func (i Int) MulRatCeil(r *big.Rat) Int {
+	return Ceil(new(big.Rat).Mul(i.Rat(), r))
+}
+
+// Floor returns the largest integer not greater than r
+func Floor(r *big.Rat) Int {
+	// euclidean division by the always positive denominator floors
+	q := big.NewInt(0).Div(r.Num(), r.Denom())
+	return Int(*q)
+}
+
+// Ceil returns the smallest integer not less than r
+func Ceil(r *big.Rat) Int {
+	return Floor(new(big.Rat).Neg(r)).Neg()
+}
+
+// MarshalCBOR encodes the value as a cbor integer, using a bignum tag only
+// when it exceeds 64 bits
+func (i Int) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(i.BigInt())
+}
+
 func (i Int) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
 	item.N = aws.String(i.BigInt().String())
 	return nil
@@ -75,6 +186,28 @@ func (i Int) MarshalJSON() ([]byte, error) {
 	return []byte(s), nil
 }
 
+func (i Int) MarshalText() ([]byte, error) {
+	return []byte(i.BigInt().String()), nil
+}
+
+// Scan implements sql.Scanner for integer, decimal string and NULL columns
+func (i *Int) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*i = Int{}
+		return nil
+	case int64:
+		*i = Int64(v)
+		return nil
+	case []byte:
+		return i.UnmarshalText(v)
+	case string:
+		return i.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("unable to scan %T into Int", src)
+	}
+}
+
 func (i Int) String() string {
 	return i.BigInt().String()
 }
@@ -120,3 +253,28 @@ func (i *Int) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+func (i *Int) UnmarshalCBOR(data []byte) error {
+	var v big.Int
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to decode number: %w", err)
+	}
+	*i = Int(v)
+	return nil
+}
+
+func (i *Int) UnmarshalText(data []byte) error {
+	s := string(data)
+	v, ok := big.NewInt(0).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("failed to parse number, %v", s)
+	}
+	*i = Int(*v)
+	return nil
+}
+
+// Value implements driver.Valuer; values are stored as decimal strings so
+// that quantities beyond 64 bits fit numeric columns
+func (i Int) Value() (driver.Value, error) {
+	return i.BigInt().String(), nil
+}
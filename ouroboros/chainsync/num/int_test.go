@@ -15,9 +15,13 @@
 package num
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/fxamacker/cbor/v2"
 )
 
 type Value struct {
@@ -65,3 +69,131 @@ func TestNew(t *testing.T) {
 		t.Fatalf("got %v; want %v", got, want)
 	}
 }
+
+func TestArithmetic(t *testing.T) {
+	a := Int64(-7)
+	b := Int64(2)
+
+	if got, want := a.Mul(b).String(), "-14"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := a.Quo(b).String(), "-3"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := a.Rem(b).String(), "-1"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := a.Cmp(b), -1; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := a.Neg().Abs().Sign(), 1; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if !(Int{}).IsZero() {
+		t.Fatalf("got false; want true")
+	}
+}
+
+func TestChecked(t *testing.T) {
+	large, _ := New("18446744073709551616") // 2^64
+
+	if _, err := large.CheckedUint64(); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+	if _, err := large.Sub(Int64(1)).CheckedInt64(); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+	if _, err := Int64(-1).CheckedUint64(); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+
+	v, err := large.Sub(Int64(1)).CheckedUint64()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := v, uint64(18446744073709551615); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestRational(t *testing.T) {
+	tests := []struct {
+		value string
+		rat   string
+		floor string
+		ceil  string
+	}{
+		{value: "10", rat: "1/3", floor: "3", ceil: "4"},
+		{value: "9", rat: "1/3", floor: "3", ceil: "3"},
+		{value: "-10", rat: "1/3", floor: "-4", ceil: "-3"},
+		{value: "200000", rat: "0.0577", floor: "11540", ceil: "11540"},
+	}
+
+	for _, tc := range tests {
+		v, _ := New(tc.value)
+		r, _ := new(big.Rat).SetString(tc.rat)
+
+		if got, want := v.MulRatFloor(r).String(), tc.floor; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := v.MulRatCeil(r).String(), tc.ceil; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	for _, s := range []string{"0", "-1", "23", "18446744073709551615", "18446744073709551616", "-18446744073709551617"} {
+		want, _ := New(s)
+
+		data, err := cbor.Marshal(want)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		var fromCBOR Int
+		if err := cbor.Unmarshal(data, &fromCBOR); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got := fromCBOR.String(); got != s {
+			t.Fatalf("got %v; want %v", got, s)
+		}
+
+		text, err := want.MarshalText()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		var fromText Int
+		if err := fromText.UnmarshalText(text); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got := fromText.String(); got != s {
+			t.Fatalf("got %v; want %v", got, s)
+		}
+
+		value, err := want.Value()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		var fromSQL Int
+		if err := fromSQL.Scan(value); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got := fromSQL.String(); got != s {
+			t.Fatalf("got %v; want %v", got, s)
+		}
+	}
+
+	// small values use the plain cbor integer encoding
+	data, _ := cbor.Marshal(Int64(23))
+	if got, want := hex.EncodeToString(data), "17"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	var i Int
+	if err := i.Scan(int64(42)); err != nil || i.String() != "42" {
+		t.Fatalf("got %v, %v; want 42, nil", i, err)
+	}
+	if err := i.Scan(1.5); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutusdata
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/cbor"
+)
+
+const (
+	tagConstrGeneral = 102
+	tagConstr0       = 121  // constructors 0 - 6
+	tagConstr7       = 1280 // constructors 7 - 127
+
+	maxChunkSize = 64
+)
+
+// Decode decodes cbor encoded plutus data
+func Decode(data []byte) (Data, error) {
+	r := cbor.NewReader(data)
+	v, err := decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode plutus data: %w", err)
+	}
+	if !r.Done() {
+		return nil, fmt.Errorf("failed to decode plutus data: %v trailing bytes", r.Remaining())
+	}
+	return v, nil
+}
+
+// DecodeHex decodes hex encoded cbor plutus data
+func DecodeHex(s string) (Data, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode plutus data: %w", err)
+	}
+	return Decode(data)
+}
+
+// Encode returns the cbor encoding of the plutus data
+func Encode(d Data) []byte {
+	if d == nil {
+		return nil
+	}
+	return d.appendCBOR(nil)
+}
+
+func (c Constr) appendCBOR(b []byte) []byte {
+	switch {
+	case c.Index < 7:
+		b = cbor.AppendHead(b, cbor.MajorTag, tagConstr0+c.Index)
+	case c.Index < 128:
+		b = cbor.AppendHead(b, cbor.MajorTag, tagConstr7+c.Index-7)
+	default:
+		b = cbor.AppendHead(b, cbor.MajorTag, tagConstrGeneral)
+		b = cbor.AppendHead(b, cbor.MajorArray, 2)
+		b = cbor.AppendHead(b, cbor.MajorUint, c.Index)
+	}
+	return appendItems(b, c.Fields, c.Definite)
+}
+
+func (m Map) appendCBOR(b []byte) []byte {
+	if m.Indefinite {
+		b = append(b, cbor.MajorMap<<5|cbor.Indefinite)
+	} else {
+		b = cbor.AppendHead(b, cbor.MajorMap, uint64(len(m.Pairs)))
+	}
+	for _, pair := range m.Pairs {
+		b = pair.Key.appendCBOR(b)
+		b = pair.Value.appendCBOR(b)
+	}
+	if m.Indefinite {
+		b = append(b, cbor.Break)
+	}
+	return b
+}
+
+func (l List) appendCBOR(b []byte) []byte {
+	return appendItems(b, l.Items, l.Definite)
+}
+
+func (i Integer) appendCBOR(b []byte) []byte {
+	v := i.Int()
+	if v.Sign() >= 0 {
+		if v.IsUint64() {
+			return cbor.AppendHead(b, cbor.MajorUint, v.Uint64())
+		}
+		b = cbor.AppendHead(b, cbor.MajorTag, cbor.TagPositiveBignum)
+		return Bytes(v.Bytes()).appendCBOR(b)
+	}
+
+	n := new(big.Int).Neg(v)
+	n.Sub(n, big.NewInt(1))
+	if n.IsUint64() {
+		return cbor.AppendHead(b, cbor.MajorNegInt, n.Uint64())
+	}
+	b = cbor.AppendHead(b, cbor.MajorTag, cbor.TagNegativeBignum)
+	return Bytes(n.Bytes()).appendCBOR(b)
+}
+
+func (bs Bytes) appendCBOR(b []byte) []byte {
+	if len(bs) <= maxChunkSize {
+		b = cbor.AppendHead(b, cbor.MajorBytes, uint64(len(bs)))
+		return append(b, bs...)
+	}
+
+	// the ledger limits byte strings to 64 byte chunks
+	b = append(b, cbor.MajorBytes<<5|cbor.Indefinite)
+	for chunk := []byte(bs); len(chunk) > 0; {
+		n := len(chunk)
+		if n > maxChunkSize {
+			n = maxChunkSize
+		}
+		b = cbor.AppendHead(b, cbor.MajorBytes, uint64(n))
+		b = append(b, chunk[:n]...)
+		chunk = chunk[n:]
+	}
+	return append(b, cbor.Break)
+}
+
+func appendItems(b []byte, items []Data, definite bool) []byte {
+	if definite || len(items) == 0 {
+		b = cbor.AppendHead(b, cbor.MajorArray, uint64(len(items)))
+	} else {
+		b = append(b, cbor.MajorArray<<5|cbor.Indefinite)
+	}
+	for _, item := range items {
+		b = item.appendCBOR(b)
+	}
+	if !definite && len(items) > 0 {
+		b = append(b, cbor.Break)
+	}
+	return b
+}
+
+func decode(r *cbor.Reader) (Data, error) {
+	major, err := r.PeekMajor()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cbor.MajorUint, cbor.MajorNegInt:
+		v, err := r.Int()
+		if err != nil {
+			return nil, err
+		}
+		return Integer{Value: v}, nil
+
+	case cbor.MajorBytes:
+		v, err := r.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		return Bytes(v), nil
+
+	case cbor.MajorArray:
+		items, definite, err := decodeItems(r)
+		return List{Items: items, Definite: definite && len(items) > 0}, err
+
+	case cbor.MajorMap:
+		n, indefinite, err := r.Expect(cbor.MajorMap)
+		if err != nil {
+			return nil, err
+		}
+		m := Map{Indefinite: indefinite}
+		err = r.Items(n, indefinite, func(int) error {
+			var pair Pair
+			if pair.Key, err = decode(r); err != nil {
+				return err
+			}
+			if pair.Value, err = decode(r); err != nil {
+				return err
+			}
+			m.Pairs = append(m.Pairs, pair)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+
+	case cbor.MajorTag:
+		tag, err := r.Tag()
+		if err != nil {
+			return nil, err
+		}
+		return decodeTag(tag, r)
+
+	default:
+		return nil, fmt.Errorf("unexpected major type, %v", major)
+	}
+}
+
+func decodeTag(tag uint64, r *cbor.Reader) (Data, error) {
+	switch {
+	case tag == cbor.TagPositiveBignum || tag == cbor.TagNegativeBignum:
+		v, err := r.Bignum(tag)
+		if err != nil {
+			return nil, err
+		}
+		return Integer{Value: v}, nil
+
+	case tag >= tagConstr0 && tag < tagConstr0+7:
+		return decodeConstr(tag-tagConstr0, r)
+
+	case tag >= tagConstr7 && tag < tagConstr7+121:
+		return decodeConstr(tag-tagConstr7+7, r)
+
+	case tag == tagConstrGeneral:
+		n, _, err := r.Expect(cbor.MajorArray)
+		if err != nil {
+			return nil, err
+		}
+		if n != 2 {
+			return nil, fmt.Errorf("expected [index, fields] for constructor")
+		}
+		index, err := r.Uint()
+		if err != nil {
+			return nil, fmt.Errorf("invalid constructor index: %w", err)
+		}
+		return decodeConstr(index, r)
+
+	default:
+		return nil, fmt.Errorf("unexpected tag, %v", tag)
+	}
+}
+
+func decodeConstr(index uint64, r *cbor.Reader) (Data, error) {
+	if major, err := r.PeekMajor(); err != nil {
+		return nil, err
+	} else if major != cbor.MajorArray {
+		return nil, fmt.Errorf("expected constructor fields")
+	}
+	fields, definite, err := decodeItems(r)
+	if err != nil {
+		return nil, err
+	}
+	return Constr{Index: index, Fields: fields, Definite: definite && len(fields) > 0}, nil
+}
+
+// decodeItems reads an array; definite is false for indefinite length arrays
+func decodeItems(r *cbor.Reader) (items []Data, definite bool, err error) {
+	n, indefinite, err := r.Expect(cbor.MajorArray)
+	if err != nil {
+		return nil, false, err
+	}
+	err = r.Items(n, indefinite, func(int) error {
+		item, err := decode(r)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return items, !indefinite, nil
+}
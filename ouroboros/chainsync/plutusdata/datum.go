@@ -0,0 +1,90 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutusdata
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Datum is plutus data along with the bytes it was decoded from.  Hashes must
+// be computed over the original bytes as re-encoding need not reproduce them
+type Datum struct {
+	Data Data
+	raw  []byte
+}
+
+// NewDatum wraps data, using its canonical encoding as the original bytes
+func NewDatum(d Data) Datum {
+	return Datum{Data: d, raw: Encode(d)}
+}
+
+// DecodeDatum decodes hex encoded cbor e.g. TxOut.Datum or Witness.Datums
+func DecodeDatum(s string) (Datum, error) {
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return Datum{}, fmt.Errorf("failed to decode datum: %w", err)
+	}
+
+	d, err := Decode(raw)
+	if err != nil {
+		return Datum{}, err
+	}
+	return Datum{Data: d, raw: raw}, nil
+}
+
+// CBOR returns the original bytes
+func (d Datum) CBOR() []byte {
+	if d.raw == nil {
+		return Encode(d.Data)
+	}
+	return d.raw
+}
+
+// Hex returns the original bytes, hex encoded
+func (d Datum) Hex() string {
+	return hex.EncodeToString(d.CBOR())
+}
+
+// Hash returns the hex encoded blake2b-256 datum hash
+func (d Datum) Hash() string {
+	return Hash(d.CBOR())
+}
+
+// Hash returns the hex encoded blake2b-256 hash of cbor encoded plutus data
+func Hash(data []byte) string {
+	sum := blake2b.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (d Datum) MarshalJSON() ([]byte, error) {
+	if d.Data == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Data)
+}
+
+// UnmarshalJSON decodes the cardano-cli detailed json schema
+func (d *Datum) UnmarshalJSON(data []byte) error {
+	v, err := FromJSON(data)
+	if err != nil {
+		return err
+	}
+	*d = NewDatum(v)
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutusdata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// The detailed json schema used by cardano-cli e.g.
+//
+//	{"constructor": 0, "fields": [{"int": 1}, {"bytes": "cafe"}]}
+//	{"map": [{"k": {"int": 1}, "v": {"list": []}}]}
+
+type jsonConstr struct {
+	Constructor uint64 `json:"constructor"`
+	Fields      []Data `json:"fields"`
+}
+
+type jsonPair struct {
+	Key   Data `json:"k"`
+	Value Data `json:"v"`
+}
+
+func (c Constr) MarshalJSON() ([]byte, error) {
+	fields := c.Fields
+	if fields == nil {
+		fields = []Data{}
+	}
+	return json.Marshal(jsonConstr{Constructor: c.Index, Fields: fields})
+}
+
+func (m Map) MarshalJSON() ([]byte, error) {
+	pairs := make([]jsonPair, 0, len(m.Pairs))
+	for _, pair := range m.Pairs {
+		pairs = append(pairs, jsonPair{Key: pair.Key, Value: pair.Value})
+	}
+	return json.Marshal(map[string][]jsonPair{"map": pairs})
+}
+
+func (l List) MarshalJSON() ([]byte, error) {
+	items := l.Items
+	if items == nil {
+		items = []Data{}
+	}
+	return json.Marshal(map[string][]Data{"list": items})
+}
+
+func (i Integer) MarshalJSON() ([]byte, error) {
+	return []byte(`{"int":` + i.Int().String() + `}`), nil
+}
+
+func (bs Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"bytes": hex.EncodeToString(bs)})
+}
+
+// FromJSON decodes plutus data from the cardano-cli detailed json schema
+func FromJSON(data []byte) (Data, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode plutus data json: %w", err)
+	}
+
+	if v, ok := raw["constructor"]; ok {
+		var index uint64
+		if err := json.Unmarshal(v, &index); err != nil {
+			return nil, fmt.Errorf("failed to decode plutus data json constructor: %w", err)
+		}
+		fields, err := fromJSONItems(raw["fields"])
+		if err != nil {
+			return nil, err
+		}
+		return Constr{Index: index, Fields: fields}, nil
+	}
+
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("failed to decode plutus data json: expected exactly one key, %v", string(data))
+	}
+
+	for key, v := range raw {
+		switch key {
+		case "map":
+			var pairs []map[string]json.RawMessage
+			if err := json.Unmarshal(v, &pairs); err != nil {
+				return nil, fmt.Errorf("failed to decode plutus data json map: %w", err)
+			}
+			m := Map{}
+			for _, pair := range pairs {
+				key, err := FromJSON(pair["k"])
+				if err != nil {
+					return nil, err
+				}
+				value, err := FromJSON(pair["v"])
+				if err != nil {
+					return nil, err
+				}
+				m.Pairs = append(m.Pairs, Pair{Key: key, Value: value})
+			}
+			return m, nil
+
+		case "list":
+			items, err := fromJSONItems(v)
+			if err != nil {
+				return nil, err
+			}
+			return List{Items: items}, nil
+
+		case "int":
+			n, ok := new(big.Int).SetString(string(bytes.TrimSpace(v)), 10)
+			if !ok {
+				return nil, fmt.Errorf("failed to decode plutus data json int, %v", string(v))
+			}
+			return Integer{Value: n}, nil
+
+		case "bytes":
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return nil, fmt.Errorf("failed to decode plutus data json bytes: %w", err)
+			}
+			bs, err := hex.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode plutus data json bytes: %w", err)
+			}
+			return Bytes(bs), nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to decode plutus data json: unknown schema, %v", string(data))
+}
+
+func fromJSONItems(data json.RawMessage) ([]Data, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode plutus data json list: %w", err)
+	}
+
+	var items []Data
+	for _, item := range raw {
+		v, err := FromJSON(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
@@ -0,0 +1,603 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutusdata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshaler is implemented by types that encode themselves as plutus data
+type Marshaler interface {
+	MarshalPlutusData() (Data, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from plutus data
+type Unmarshaler interface {
+	UnmarshalPlutusData(Data) error
+}
+
+var (
+	dataType        = reflect.TypeOf((*Data)(nil)).Elem()
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	bigIntType      = reflect.TypeOf(big.Int{})
+)
+
+// options holds the comma separated options of a plutus struct tag
+//
+//	constr=N  on the blank field, _, sets the constructor index of the struct;
+//	          on a field of a sum type, the index of that alternative
+//	sum       on the blank field, _, marks the struct as a sum type
+//	optional  the pointer field is a Maybe; nil is Nothing
+//	enum      the integer field is a nullary constructor, Constr N []
+//	hex       the string field holds hex encoded bytes
+//	-         the field is ignored
+type options struct {
+	skip     bool
+	constr   *uint64
+	sum      bool
+	optional bool
+	enum     bool
+	hex      bool
+}
+
+func parseTag(tag string) (options, error) {
+	var opts options
+	if tag == "" {
+		return opts, nil
+	}
+	if tag == "-" {
+		opts.skip = true
+		return opts, nil
+	}
+
+	for _, opt := range strings.Split(tag, ",") {
+		switch opt = strings.TrimSpace(opt); {
+		case opt == "":
+		case opt == "sum":
+			opts.sum = true
+		case opt == "optional":
+			opts.optional = true
+		case opt == "enum":
+			opts.enum = true
+		case opt == "hex":
+			opts.hex = true
+		case strings.HasPrefix(opt, "constr="):
+			index, err := strconv.ParseUint(strings.TrimPrefix(opt, "constr="), 10, 64)
+			if err != nil {
+				return options{}, fmt.Errorf("invalid constructor index, %v", opt)
+			}
+			opts.constr = &index
+		default:
+			return options{}, fmt.Errorf("unknown option, %v", opt)
+		}
+	}
+	return opts, nil
+}
+
+type structInfo struct {
+	index  uint64
+	sum    bool
+	fields []fieldInfo
+}
+
+type fieldInfo struct {
+	index int
+	name  string
+	opts  options
+}
+
+func getStructInfo(t reflect.Type) (structInfo, error) {
+	var info structInfo
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		opts, err := parseTag(f.Tag.Get("plutus"))
+		if err != nil {
+			return structInfo{}, fmt.Errorf("%v.%v: %w", t, f.Name, err)
+		}
+
+		if f.Name == "_" {
+			if opts.constr != nil {
+				info.index = *opts.constr
+			}
+			info.sum = info.sum || opts.sum
+			continue
+		}
+		if opts.skip || f.PkgPath != "" {
+			continue
+		}
+		info.fields = append(info.fields, fieldInfo{index: i, name: f.Name, opts: opts})
+	}
+
+	if info.sum {
+		for _, f := range info.fields {
+			ft := t.Field(f.index).Type
+			if f.opts.constr == nil || ft.Kind() != reflect.Ptr || ft.Elem().Kind() != reflect.Struct {
+				return structInfo{}, fmt.Errorf("%v.%v: sum type alternatives must be struct pointers tagged with constr=N", t, f.name)
+			}
+		}
+	}
+	return info, nil
+}
+
+// Marshal returns the plutus data encoding of v.  Go values are mapped as
+// follows
+//
+//	struct              Constr with exported fields in order; index 0 unless
+//	                    set via the blank field, _ struct{} `plutus:"constr=N"`
+//	sum type struct     Constr N of the single non-nil alternative
+//	bool                Constr 0 [] for false, Constr 1 [] for true
+//	integers, big.Int   Integer; with enum, Constr N []
+//	string, []byte      Bytes; with hex, strings are hex decoded
+//	slices, arrays      List
+//	maps                Map, sorted by encoded key
+//	Data                as is
+//
+// Pointers tagged optional encode as Maybe, Constr 0 [v] or Constr 1 []
+func Marshal(v interface{}) (Data, error) {
+	d, err := marshalValue(reflect.ValueOf(v), options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plutus data: %w", err)
+	}
+	return d, nil
+}
+
+func marshalValue(v reflect.Value, opts options) (Data, error) {
+	if !v.IsValid() {
+		return nil, fmt.Errorf("unable to marshal nil")
+	}
+
+	if opts.optional {
+		if v.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("optional requires a pointer, got %v", v.Type())
+		}
+		if v.IsNil() {
+			return Constr{Index: 1}, nil
+		}
+		opts.optional = false
+		d, err := marshalValue(v.Elem(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return NewConstr(0, d), nil
+	}
+
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, fmt.Errorf("unable to marshal nil %v", v.Type())
+	}
+	if v.Type().Implements(marshalerType) {
+		return v.Interface().(Marshaler).MarshalPlutusData()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler).MarshalPlutusData()
+	}
+	if v.Type().Implements(dataType) {
+		return v.Interface().(Data), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return marshalValue(v.Elem(), opts)
+
+	case reflect.Bool:
+		if v.Bool() {
+			return Constr{Index: 1}, nil
+		}
+		return Constr{Index: 0}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if opts.enum {
+			if v.Int() < 0 {
+				return nil, fmt.Errorf("invalid enum value, %v", v.Int())
+			}
+			return Constr{Index: uint64(v.Int())}, nil
+		}
+		return NewInteger(v.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if opts.enum {
+			return Constr{Index: v.Uint()}, nil
+		}
+		return Integer{Value: new(big.Int).SetUint64(v.Uint())}, nil
+
+	case reflect.String:
+		if opts.hex {
+			bs, err := hex.DecodeString(v.String())
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode hex string: %w", err)
+			}
+			return Bytes(bs), nil
+		}
+		return Bytes(v.String()), nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bs := make(Bytes, v.Len())
+			for i := range bs {
+				bs[i] = byte(v.Index(i).Uint())
+			}
+			return bs, nil
+		}
+		items := make([]Data, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := marshalValue(v.Index(i), options{})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return List{Items: items}, nil
+
+	case reflect.Map:
+		return marshalMap(v)
+
+	case reflect.Struct:
+		if v.Type() == bigIntType {
+			i := v.Interface().(big.Int)
+			return NewBigInteger(&i), nil
+		}
+		return marshalStruct(v)
+
+	default:
+		return nil, fmt.Errorf("unsupported type, %v", v.Type())
+	}
+}
+
+func marshalMap(v reflect.Value) (Data, error) {
+	type entry struct {
+		pair Pair
+		key  []byte
+	}
+
+	var entries []entry
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := marshalValue(iter.Key(), options{})
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalValue(iter.Value(), options{})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{pair: Pair{Key: key, Value: value}, key: Encode(key)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	var m Map
+	for _, e := range entries {
+		m.Pairs = append(m.Pairs, e.pair)
+	}
+	return m, nil
+}
+
+func marshalStruct(v reflect.Value) (Data, error) {
+	info, err := getStructInfo(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	if !info.sum {
+		fields, err := marshalFields(v, info)
+		if err != nil {
+			return nil, err
+		}
+		return Constr{Index: info.index, Fields: fields}, nil
+	}
+
+	var (
+		c     Constr
+		found bool
+	)
+	for _, f := range info.fields {
+		fv := v.Field(f.index)
+		if fv.IsNil() {
+			continue
+		}
+		if found {
+			return nil, fmt.Errorf("%v: more than one alternative set", v.Type())
+		}
+
+		alt := fv.Elem()
+		altInfo, err := getStructInfo(alt.Type())
+		if err != nil {
+			return nil, err
+		}
+		fields, err := marshalFields(alt, altInfo)
+		if err != nil {
+			return nil, err
+		}
+		c, found = Constr{Index: *f.opts.constr, Fields: fields}, true
+	}
+	if !found {
+		return nil, fmt.Errorf("%v: no alternative set", v.Type())
+	}
+	return c, nil
+}
+
+func marshalFields(v reflect.Value, info structInfo) ([]Data, error) {
+	var fields []Data
+	for _, f := range info.fields {
+		d, err := marshalValue(v.Field(f.index), f.opts)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %w", v.Type(), f.name, err)
+		}
+		fields = append(fields, d)
+	}
+	return fields, nil
+}
+
+// Unmarshal decodes plutus data into the value pointed to by v using the
+// mapping described by Marshal.  Constructor indexes and field counts must
+// match exactly
+func Unmarshal(d Data, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("failed to unmarshal plutus data: expected non-nil pointer, got %T", v)
+	}
+	if err := unmarshalValue(d, rv.Elem(), options{}); err != nil {
+		return fmt.Errorf("failed to unmarshal plutus data: %w", err)
+	}
+	return nil
+}
+
+func unmarshalValue(d Data, v reflect.Value, opts options) error {
+	if d == nil {
+		return fmt.Errorf("unable to unmarshal nil into %v", v.Type())
+	}
+
+	if opts.optional {
+		if v.Kind() != reflect.Ptr {
+			return fmt.Errorf("optional requires a pointer, got %v", v.Type())
+		}
+		c, ok := d.(Constr)
+		switch {
+		case ok && c.Index == 1 && len(c.Fields) == 0:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		case ok && c.Index == 0 && len(c.Fields) == 1:
+			d, opts.optional = c.Fields[0], false
+		default:
+			return fmt.Errorf("expected Maybe for %v", v.Type())
+		}
+	}
+
+	if v.Kind() != reflect.Ptr && v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalPlutusData(d)
+	}
+	if v.Kind() == reflect.Interface && (v.NumMethod() == 0 || v.Type() == dataType) {
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+	if v.Kind() != reflect.Interface && v.Type().Implements(dataType) && reflect.TypeOf(d).AssignableTo(v.Type()) {
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalValue(d, v.Elem(), opts)
+
+	case reflect.Bool:
+		c, ok := d.(Constr)
+		if !ok || c.Index > 1 || len(c.Fields) != 0 {
+			return typeError(d, v)
+		}
+		v.SetBool(c.Index == 1)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := unmarshalInt(d, v, opts)
+		if err != nil {
+			return err
+		}
+		if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+			return fmt.Errorf("value %v overflows %v", n, v.Type())
+		}
+		v.SetInt(n.Int64())
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := unmarshalInt(d, v, opts)
+		if err != nil {
+			return err
+		}
+		if !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("value %v overflows %v", n, v.Type())
+		}
+		v.SetUint(n.Uint64())
+		return nil
+
+	case reflect.String:
+		bs, ok := d.(Bytes)
+		if !ok {
+			return typeError(d, v)
+		}
+		if opts.hex {
+			v.SetString(hex.EncodeToString(bs))
+		} else {
+			v.SetString(string(bs))
+		}
+		return nil
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bs, ok := d.(Bytes)
+			if !ok {
+				return typeError(d, v)
+			}
+			v.Set(reflect.MakeSlice(v.Type(), len(bs), len(bs)))
+			setBytes(v, bs)
+			return nil
+		}
+		list, ok := d.(List)
+		if !ok {
+			return typeError(d, v)
+		}
+		s := reflect.MakeSlice(v.Type(), len(list.Items), len(list.Items))
+		for i, item := range list.Items {
+			if err := unmarshalValue(item, s.Index(i), options{}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bs, ok := d.(Bytes)
+			if !ok {
+				return typeError(d, v)
+			}
+			if len(bs) != v.Len() {
+				return fmt.Errorf("expected %v bytes for %v, got %v", v.Len(), v.Type(), len(bs))
+			}
+			setBytes(v, bs)
+			return nil
+		}
+		list, ok := d.(List)
+		if !ok {
+			return typeError(d, v)
+		}
+		if len(list.Items) != v.Len() {
+			return fmt.Errorf("expected %v items for %v, got %v", v.Len(), v.Type(), len(list.Items))
+		}
+		for i, item := range list.Items {
+			if err := unmarshalValue(item, v.Index(i), options{}); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		m, ok := d.(Map)
+		if !ok {
+			return typeError(d, v)
+		}
+		mv := reflect.MakeMapWithSize(v.Type(), len(m.Pairs))
+		for _, pair := range m.Pairs {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := unmarshalValue(pair.Key, key, options{}); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := unmarshalValue(pair.Value, value, options{}); err != nil {
+				return err
+			}
+			mv.SetMapIndex(key, value)
+		}
+		v.Set(mv)
+		return nil
+
+	case reflect.Struct:
+		if v.Type() == bigIntType {
+			i, ok := d.(Integer)
+			if !ok {
+				return typeError(d, v)
+			}
+			v.Addr().Interface().(*big.Int).Set(i.Int())
+			return nil
+		}
+		return unmarshalStruct(d, v)
+
+	default:
+		return fmt.Errorf("unsupported type, %v", v.Type())
+	}
+}
+
+func unmarshalInt(d Data, v reflect.Value, opts options) (*big.Int, error) {
+	if opts.enum {
+		c, ok := d.(Constr)
+		if !ok || len(c.Fields) != 0 {
+			return nil, typeError(d, v)
+		}
+		return new(big.Int).SetUint64(c.Index), nil
+	}
+
+	i, ok := d.(Integer)
+	if !ok {
+		return nil, typeError(d, v)
+	}
+	return i.Int(), nil
+}
+
+func unmarshalStruct(d Data, v reflect.Value) error {
+	c, ok := d.(Constr)
+	if !ok {
+		return typeError(d, v)
+	}
+	info, err := getStructInfo(v.Type())
+	if err != nil {
+		return err
+	}
+	if !info.sum {
+		if c.Index != info.index {
+			return fmt.Errorf("expected constructor %v for %v, got %v", info.index, v.Type(), c.Index)
+		}
+		return unmarshalFields(c.Fields, v, info)
+	}
+
+	for _, f := range info.fields {
+		if *f.opts.constr != c.Index {
+			continue
+		}
+
+		alt := reflect.New(v.Type().Field(f.index).Type.Elem())
+		altInfo, err := getStructInfo(alt.Elem().Type())
+		if err != nil {
+			return err
+		}
+		if err := unmarshalFields(c.Fields, alt.Elem(), altInfo); err != nil {
+			return err
+		}
+		v.Set(reflect.Zero(v.Type()))
+		v.Field(f.index).Set(alt)
+		return nil
+	}
+	return fmt.Errorf("unknown constructor %v for %v", c.Index, v.Type())
+}
+
+func unmarshalFields(fields []Data, v reflect.Value, info structInfo) error {
+	if len(fields) != len(info.fields) {
+		return fmt.Errorf("expected %v fields for %v, got %v", len(info.fields), v.Type(), len(fields))
+	}
+	for i, f := range info.fields {
+		if err := unmarshalValue(fields[i], v.Field(f.index), f.opts); err != nil {
+			return fmt.Errorf("%v.%v: %w", v.Type(), f.name, err)
+		}
+	}
+	return nil
+}
+
+func setBytes(v reflect.Value, bs Bytes) {
+	for i, b := range bs {
+		v.Index(i).SetUint(uint64(b))
+	}
+}
+
+func typeError(d Data, v reflect.Value) error {
+	return fmt.Errorf("cannot unmarshal %T into %v", d, v.Type())
+}
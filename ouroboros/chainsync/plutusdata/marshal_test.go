@@ -0,0 +1,254 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutusdata
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAsset struct {
+	Policy string `plutus:"hex"`
+	Name   string `plutus:"hex"`
+}
+
+type testPoolDatum struct {
+	Coins struct {
+		A testAsset
+		B testAsset
+	}
+	Ident         string `plutus:"hex"`
+	CirculatingLP *big.Int
+	Fee           struct {
+		Num int64
+		Den int64
+	}
+}
+
+type testCredential struct {
+	_      struct{} `plutus:"sum"`
+	PubKey *struct {
+		Hash string `plutus:"hex"`
+	} `plutus:"constr=0"`
+	Script *struct {
+		Hash string `plutus:"hex"`
+	} `plutus:"constr=1"`
+}
+
+type testStakingCredential struct {
+	_       struct{}                                   `plutus:"sum"`
+	Hash    *struct{ Credential testCredential }       `plutus:"constr=0"`
+	Pointer *struct{ Slot, TxIndex, CertIndex uint64 } `plutus:"constr=1"`
+}
+
+type testAddress struct {
+	Payment testCredential
+	Staking *testStakingCredential `plutus:"optional"`
+}
+
+type testCoin int
+
+const (
+	testCoinA testCoin = iota
+	testCoinB
+)
+
+type testOrderDatum struct {
+	Ident     string `plutus:"hex"`
+	Addresses struct {
+		Destination struct {
+			Address   testAddress
+			DatumHash *string `plutus:"optional,hex"`
+		}
+		Alternate *string `plutus:"optional,hex"`
+	}
+	ScoopFee uint64
+	Swap     struct {
+		Coin       testCoin `plutus:"enum"`
+		Amount     uint64
+		MinReceive *uint64 `plutus:"optional"`
+	}
+}
+
+func TestUnmarshal_Scoop(t *testing.T) {
+	var (
+		pool  testPoolDatum
+		order testOrderDatum
+		asset testAsset
+	)
+
+	for hash, s := range scoopDatums(t) {
+		d, err := DecodeHex(s)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var v interface{}
+		switch hash[:8] {
+		case "4dcca434":
+			v = &pool
+		case "3d2b6dd4":
+			v = &order
+		case "04339bc4":
+			v = &asset
+		default:
+			t.Fatalf("got unexpected datum, %v", hash)
+		}
+		if err := Unmarshal(d, v); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		// round trip reproduces the original encoding
+		got, err := Marshal(v)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, s, hex.EncodeToString(Encode(got)))
+	}
+
+	assert.Equal(t, "", pool.Coins.A.Policy)
+	assert.Equal(t, "c88bbd1848db5ea665b1fffbefba86e8dcd723b5085348e8a8d2260f", pool.Coins.B.Policy)
+	assert.Equal(t, "44414e41", pool.Coins.B.Name)
+	assert.Equal(t, "17", pool.Ident)
+	assert.Equal(t, big.NewInt(11114900357), pool.CirculatingLP)
+	assert.EqualValues(t, 1, pool.Fee.Num)
+	assert.EqualValues(t, 2000, pool.Fee.Den)
+
+	address := order.Addresses.Destination.Address
+	assert.Equal(t, "17", order.Ident)
+	assert.Equal(t, "ec25585fd858fe72d6e76547343de99be6ca83d81628bb8c72a1d407", address.Payment.PubKey.Hash)
+	assert.Nil(t, address.Payment.Script)
+	assert.Equal(t, "5043f73b34eda6b0327c23d5f5c6a2e14f5e31bb82aa0a24f517dc2a", address.Staking.Hash.Credential.PubKey.Hash)
+	assert.Nil(t, order.Addresses.Destination.DatumHash)
+	assert.Nil(t, order.Addresses.Alternate)
+	assert.EqualValues(t, 2500000, order.ScoopFee)
+	assert.Equal(t, testCoinA, order.Swap.Coin)
+	assert.EqualValues(t, 200000000, order.Swap.Amount)
+	assert.EqualValues(t, 411616523, *order.Swap.MinReceive)
+
+	assert.Equal(t, "9d1cbb54faf284f5d262f591b1f9201a1858de155157dad49f3881c4", asset.Policy)
+	assert.Equal(t, "bd0a", asset.Name)
+}
+
+func TestMarshal(t *testing.T) {
+	type redeemer struct {
+		_      struct{} `plutus:"constr=2"`
+		Flag   bool
+		Labels map[string]int
+		Items  [][2]int
+		Raw    Data
+		Skip   string `plutus:"-"`
+	}
+
+	v := redeemer{
+		Flag:   true,
+		Labels: map[string]int{"b": 2, "a": -1},
+		Items:  [][2]int{{1, 2}},
+		Raw:    Bytes{0xca, 0xfe},
+		Skip:   "ignored",
+	}
+	d, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := NewConstr(2,
+		Constr{Index: 1},
+		Map{Pairs: []Pair{
+			{Key: Bytes("a"), Value: NewInteger(-1)},
+			{Key: Bytes("b"), Value: NewInteger(2)},
+		}},
+		NewList(NewList(NewInteger(1), NewInteger(2))),
+		Bytes{0xca, 0xfe},
+	)
+	assert.True(t, Equal(want, d))
+
+	var got redeemer
+	if err := Unmarshal(d, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	v.Skip = ""
+	assert.Equal(t, v, got)
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	type pair struct {
+		A, B int64
+	}
+	type small struct {
+		V int8
+	}
+	type sum struct {
+		_ struct{} `plutus:"sum"`
+		A *pair    `plutus:"constr=0"`
+	}
+
+	tests := map[string]struct {
+		Data Data
+		V    interface{}
+	}{
+		"constructor": {Data: NewConstr(1, NewInteger(1), NewInteger(2)), V: &pair{}},
+		"field count": {Data: NewConstr(0, NewInteger(1)), V: &pair{}},
+		"field type":  {Data: NewConstr(0, NewInteger(1), Bytes{}), V: &pair{}},
+		"overflow":    {Data: NewConstr(0, NewInteger(128)), V: &small{}},
+		"alternative": {Data: NewConstr(1), V: &sum{}},
+		"maybe": {Data: NewConstr(2), V: &struct {
+			V *int `plutus:"optional"`
+		}{}},
+		"bad tag": {Data: NewConstr(0, NewInteger(1)), V: &struct {
+			V int `plutus:"bogus"`
+		}{}},
+		"non-pointer": {Data: NewInteger(1), V: pair{}},
+		"unsupported": {Data: NewInteger(1), V: new(float64)},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := Unmarshal(tc.Data, tc.V); err == nil {
+				t.Fatalf("got nil; want err")
+			}
+		})
+	}
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	type sum struct {
+		_ struct{}  `plutus:"sum"`
+		A *struct{} `plutus:"constr=0"`
+		B *struct{} `plutus:"constr=1"`
+	}
+
+	tests := map[string]interface{}{
+		"nil":          nil,
+		"nil pointer":  struct{ V *int }{},
+		"no variant":   sum{},
+		"two variants": sum{A: &struct{}{}, B: &struct{}{}},
+		"bad hex": struct {
+			V string `plutus:"hex"`
+		}{V: "zz"},
+		"negative": struct {
+			V int `plutus:"enum"`
+		}{V: -1},
+	}
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Marshal(v); err == nil {
+				t.Fatalf("got nil; want err")
+			}
+		})
+	}
+}
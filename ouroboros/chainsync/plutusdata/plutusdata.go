@@ -0,0 +1,138 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plutusdata implements the plutus data model used by datums and
+// redeemers, along with its cbor and cardano-cli detailed json encodings
+package plutusdata
+
+import (
+	"math/big"
+)
+
+// Data is one of Constr, Map, List, Integer or Bytes
+type Data interface {
+	appendCBOR(b []byte) []byte
+}
+
+// Constr is a constructor application; the alternative Index of a sum type
+// with its Fields in order
+type Constr struct {
+	Index  uint64
+	Fields []Data
+	// Definite encodes fields with a definite length; by default non-empty
+	// fields use an indefinite length, matching cardano-node
+	Definite bool
+}
+
+type Map struct {
+	Pairs []Pair
+	// Indefinite encodes the map with an indefinite length
+	Indefinite bool
+}
+
+type Pair struct {
+	Key   Data
+	Value Data
+}
+
+type List struct {
+	Items []Data
+	// Definite encodes items with a definite length; by default non-empty
+	// lists use an indefinite length, matching cardano-node
+	Definite bool
+}
+
+type Integer struct {
+	Value *big.Int
+}
+
+type Bytes []byte
+
+func NewConstr(index uint64, fields ...Data) Constr {
+	return Constr{Index: index, Fields: fields}
+}
+
+func NewList(items ...Data) List {
+	return List{Items: items}
+}
+
+func NewInteger(v int64) Integer {
+	return Integer{Value: big.NewInt(v)}
+}
+
+func NewBigInteger(v *big.Int) Integer {
+	return Integer{Value: new(big.Int).Set(v)}
+}
+
+// Int returns the value; nil values are treated as zero
+func (i Integer) Int() *big.Int {
+	if i.Value == nil {
+		return new(big.Int)
+	}
+	return i.Value
+}
+
+// Get returns the value associated with the first key equal to key
+func (m Map) Get(key Data) (Data, bool) {
+	for _, pair := range m.Pairs {
+		if Equal(pair.Key, key) {
+			return pair.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Equal returns true if a and b hold the same values, ignoring encoding
+// choices such as definite vs indefinite lengths
+func Equal(a, b Data) bool {
+	switch a := a.(type) {
+	case Constr:
+		b, ok := b.(Constr)
+		return ok && a.Index == b.Index && equalAll(a.Fields, b.Fields)
+	case Map:
+		b, ok := b.(Map)
+		if !ok || len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+		for i := range a.Pairs {
+			if !Equal(a.Pairs[i].Key, b.Pairs[i].Key) || !Equal(a.Pairs[i].Value, b.Pairs[i].Value) {
+				return false
+			}
+		}
+		return true
+	case List:
+		b, ok := b.(List)
+		return ok && equalAll(a.Items, b.Items)
+	case Integer:
+		b, ok := b.(Integer)
+		return ok && a.Int().Cmp(b.Int()) == 0
+	case Bytes:
+		b, ok := b.(Bytes)
+		return ok && string(a) == string(b)
+	default:
+		return a == nil && b == nil
+	}
+}
+
+func equalAll(a, b []Data) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
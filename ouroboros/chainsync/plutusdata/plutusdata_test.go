@@ -0,0 +1,203 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutusdata
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+// scoopDatums returns the witness datums, keyed by datum hash, from the
+// chainsync test data
+func scoopDatums(t *testing.T) map[string]string {
+	data, err := os.ReadFile("../testdata/scoop.json")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var item struct {
+		Tx struct {
+			M struct {
+				Witness struct {
+					M struct {
+						Datums struct {
+							M map[string]dynamodb.AttributeValue
+						} `json:"datums"`
+					} `json:"M"`
+				} `json:"witness"`
+			} `json:"M"`
+		} `json:"tx"`
+	}
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	datums := map[string]string{}
+	for hash, v := range item.Tx.M.Witness.M.Datums.M {
+		datums[hash] = hex.EncodeToString(v.B)
+	}
+	if len(datums) == 0 {
+		t.Fatalf("got no datums; want datums")
+	}
+	return datums
+}
+
+func TestDecodeDatum_Scoop(t *testing.T) {
+	for hash, s := range scoopDatums(t) {
+		datum, err := DecodeDatum(s)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, hash, datum.Hash())
+		assert.Equal(t, s, datum.Hex())
+
+		// decoded encoding choices are preserved
+		assert.Equal(t, s, hex.EncodeToString(Encode(datum.Data)))
+
+		data, err := json.Marshal(datum)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var got Datum
+		err = json.Unmarshal(data, &got)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.True(t, Equal(datum.Data, got.Data))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	big128, _ := new(big.Int).SetString("340282366920938463463374607431768211456", 10) // 2^128
+	long := make([]byte, 70)
+
+	testCases := map[string]struct {
+		Data Data
+		Want string
+	}{
+		"empty constr": {
+			Data: NewConstr(0),
+			Want: "d87980",
+		},
+		"constr fields": {
+			Data: NewConstr(1, NewInteger(1), Bytes{0xca, 0xfe}),
+			Want: "d87a9f0142cafeff",
+		},
+		"definite constr": {
+			Data: Constr{Index: 0, Fields: []Data{NewInteger(1)}, Definite: true},
+			Want: "d8798101",
+		},
+		"constr 7": {
+			Data: NewConstr(7),
+			Want: "d9050080",
+		},
+		"constr 128": {
+			Data: NewConstr(128, NewInteger(0)),
+			Want: "d8668218809f00ff",
+		},
+		"map": {
+			Data: Map{Pairs: []Pair{{Key: NewInteger(1), Value: NewList()}}},
+			Want: "a10180",
+		},
+		"negative": {
+			Data: NewInteger(-500),
+			Want: "3901f3",
+		},
+		"bignum": {
+			Data: NewBigInteger(big128),
+			Want: "c25101" + "00000000000000000000000000000000",
+		},
+		"negative bignum": {
+			Data: NewBigInteger(new(big.Int).Neg(big128)),
+			Want: "c350" + "ffffffffffffffffffffffffffffffff",
+		},
+		"long bytes": {
+			Data: Bytes(long),
+			Want: "5f5840" + hex.EncodeToString(long[:64]) + "46" + hex.EncodeToString(long[64:]) + "ff",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := hex.EncodeToString(Encode(tc.Data))
+			assert.Equal(t, tc.Want, got)
+
+			decoded, err := DecodeHex(got)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.True(t, Equal(tc.Data, decoded))
+			assert.Equal(t, got, hex.EncodeToString(Encode(decoded)))
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"d879",         // constr without fields
+		"9f01",         // unterminated list
+		"6161",         // text is not plutus data
+		"d8799f00ff00", // trailing bytes
+		"bf01ff",       // key without value
+	} {
+		_, err := DecodeHex(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	const detailed = `{"constructor": 0, "fields": [
+	  {"map": [{"k": {"bytes": "cafe"}, "v": {"int": -1}}]},
+	  {"list": [{"int": 340282366920938463463374607431768211456}]},
+	  {"constructor": 2, "fields": []}
+	]}`
+
+	d, err := FromJSON([]byte(detailed))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	c, ok := d.(Constr)
+	assert.True(t, ok)
+	assert.Len(t, c.Fields, 3)
+
+	v, ok := c.Fields[0].(Map).Get(Bytes{0xca, 0xfe})
+	assert.True(t, ok)
+	assert.Equal(t, int64(-1), v.(Integer).Int().Int64())
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.JSONEq(t, detailed, string(data))
+
+	_, err = FromJSON([]byte(`{"string": "nope"}`))
+	assert.Error(t, err)
+}
+
+func TestNewDatum(t *testing.T) {
+	datum := NewDatum(NewConstr(0))
+	assert.Equal(t, "d87980", datum.Hex())
+	// blake2b-256 of d87980, the unit datum
+	assert.Equal(t, "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec", datum.Hash())
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePoint parses either origin or a point in the form {slot}/{hash}, the
+// form produced by MarshalText
+func ParsePoint(s string) (Point, error) {
+	if s == string(Origin.pointString) {
+		return Origin, nil
+	}
+
+	index := strings.Index(s, "/")
+	if index <= 0 {
+		return Point{}, fmt.Errorf("failed to parse point, %v: want origin or {slot}/{hash}", s)
+	}
+	slot, err := strconv.ParseUint(s[:index], 10, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("failed to parse point slot, %v: %w", s, err)
+	}
+	hash := s[index+1:]
+	if _, err := hex.DecodeString(hash); err != nil || hash == "" {
+		return Point{}, fmt.Errorf("failed to parse point hash, %v: want hex", s)
+	}
+
+	return PointStruct{Slot: slot, Hash: strings.ToLower(hash)}.Point(), nil
+}
+
+// Slot returns the slot of the point; 0 for origin
+func (p Point) Slot() uint64 {
+	if p.pointStruct == nil {
+		return 0
+	}
+	return p.pointStruct.Slot
+}
+
+// Hash returns the block hash of the point; empty for origin
+func (p Point) Hash() string {
+	if p.pointStruct == nil {
+		return ""
+	}
+	return p.pointStruct.Hash
+}
+
+// Equal returns true if both points refer to the same block.  Block numbers
+// are ignored as ogmios does not always report them
+func (p Point) Equal(that Point) bool {
+	if p.pointType != that.pointType {
+		return false
+	}
+	if p.pointType == PointTypeStruct {
+		return p.pointStruct.Slot == that.pointStruct.Slot && strings.EqualFold(p.pointStruct.Hash, that.pointStruct.Hash)
+	}
+	return p.pointString == that.pointString
+}
+
+// Compare orders points by slot, then hash, with origin first.  It returns -1,
+// 0 or +1 depending on whether p is before, equal to or after that
+func (p Point) Compare(that Point) int {
+	switch {
+	case p.pointType != PointTypeStruct && that.pointType == PointTypeStruct:
+		return -1
+	case p.pointType == PointTypeStruct && that.pointType != PointTypeStruct:
+		return 1
+	case p.Slot() < that.Slot():
+		return -1
+	case p.Slot() > that.Slot():
+		return 1
+	default:
+		return strings.Compare(strings.ToLower(p.Hash()), strings.ToLower(that.Hash()))
+	}
+}
+
+// PointKey is a comparable form of Point.  Point holds its struct form by
+// pointer, so two equal points are distinct Go map keys; use Key instead
+type PointKey struct {
+	Type   PointType
+	String PointString
+	Slot   uint64
+	Hash   string
+}
+
+// Key returns a value that may be used as a Go map key; points that are Equal
+// return the same key
+func (p Point) Key() PointKey {
+	return PointKey{
+		Type:   p.pointType,
+		String: p.pointString,
+		Slot:   p.Slot(),
+		Hash:   strings.ToLower(p.Hash()),
+	}
+}
+
+// MarshalText encodes the point as origin or {slot}/{hash}, allowing points to
+// be used in config files and as json map keys.  Go map keys should use Key
+func (p Point) MarshalText() ([]byte, error) {
+	switch p.pointType {
+	case PointTypeString:
+		return []byte(p.pointString), nil
+	case PointTypeStruct:
+		return []byte(strconv.FormatUint(p.pointStruct.Slot, 10) + "/" + p.pointStruct.Hash), nil
+	default:
+		return nil, fmt.Errorf("unable to marshal Point: unknown type")
+	}
+}
+
+func (p *Point) UnmarshalText(data []byte) error {
+	point, err := ParsePoint(string(data))
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
+
+// PointFlag implements flag.Value for a point.  String prints the point as
+// MarshalText does so the default value round trips through Set
+type PointFlag struct {
+	Point *Point
+}
+
+func (f PointFlag) String() string {
+	if f.Point == nil {
+		return ""
+	}
+	text, err := f.Point.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+func (f PointFlag) Set(s string) error {
+	if f.Point == nil {
+		return fmt.Errorf("failed to set point flag, %v: nil Point", s)
+	}
+	return f.Point.UnmarshalText([]byte(s))
+}
+
+// PointsFlag implements flag.Value for a list of points.  Set appends comma
+// separated points so the flag may be repeated; String prints the points, as
+// MarshalText does, in the same form
+type PointsFlag struct {
+	Points *Points
+}
+
+func (f PointsFlag) String() string {
+	if f.Points == nil {
+		return ""
+	}
+	var ss []string
+	for _, p := range *f.Points {
+		ss = append(ss, PointFlag{Point: &p}.String())
+	}
+	return strings.Join(ss, ",")
+}
+
+func (f PointsFlag) Set(s string) error {
+	if f.Points == nil {
+		return fmt.Errorf("failed to set points flag, %v: nil Points", s)
+	}
+	for _, item := range strings.Split(s, ",") {
+		point, err := ParsePoint(strings.TrimSpace(item))
+		if err != nil {
+			return err
+		}
+		*f.Points = append(*f.Points, point)
+	}
+	return nil
+}
+
+// Contains returns true if an equal point is present
+func (pp Points) Contains(p Point) bool {
+	for _, point := range pp {
+		if point.Equal(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dedupe returns the points with duplicates removed, preserving order
+func (pp Points) Dedupe() Points {
+	var points Points
+	for _, p := range pp {
+		if !points.Contains(p) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// Newest returns the point with the highest slot; false if there are no points
+func (pp Points) Newest() (Point, bool) {
+	if len(pp) == 0 {
+		return Point{}, false
+	}
+
+	newest := pp[0]
+	for _, p := range pp[1:] {
+		if p.Compare(newest) > 0 {
+			newest = p
+		}
+	}
+	return newest, true
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePoint(t *testing.T) {
+	const hash = "a4b3c1b2d5e5f0c4d2c1e1f7a4b3c1b2d5e5f0c4d2c1e1f7a4b3c1b2d5e5f0c4"
+
+	testCases := map[string]struct {
+		input   string
+		want    Point
+		wantErr bool
+	}{
+		"origin": {
+			input: "origin",
+			want:  Origin,
+		},
+		"struct": {
+			input: "123/" + hash,
+			want:  PointStruct{Slot: 123, Hash: hash}.Point(),
+		},
+		"missing hash": {
+			input:   "123/",
+			wantErr: true,
+		},
+		"missing slot": {
+			input:   "/" + hash,
+			wantErr: true,
+		},
+		"invalid hash": {
+			input:   "123/xyz",
+			wantErr: true,
+		},
+		"invalid slot": {
+			input:   "-1/" + hash,
+			wantErr: true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, err := ParsePoint(tc.input)
+			if tc.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.True(t, tc.want.Equal(got), "got %v; want %v", got, tc.want)
+
+			text, err := got.MarshalText()
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, tc.input, string(text))
+		})
+	}
+}
+
+func TestPoint_Equal(t *testing.T) {
+	a := PointStruct{Slot: 1, Hash: "ab", BlockNo: 7}.Point()
+	b := PointStruct{Slot: 1, Hash: "AB"}.Point()
+	c := PointStruct{Slot: 2, Hash: "ab"}.Point()
+
+	assert.True(t, a.Equal(b))
+	assert.False(t, a.Equal(c))
+	assert.False(t, a.Equal(Origin))
+	assert.True(t, Origin.Equal(PointString("origin").Point()))
+
+	assert.Equal(t, -1, Origin.Compare(a))
+	assert.Equal(t, 0, a.Compare(b))
+	assert.Equal(t, 1, c.Compare(a))
+	assert.EqualValues(t, 2, c.Slot())
+	assert.EqualValues(t, 0, Origin.Slot())
+	assert.Equal(t, "", Origin.Hash())
+}
+
+func TestPoint_Text(t *testing.T) {
+	point := PointStruct{Slot: 1, Hash: "ab"}.Point()
+
+	t.Run("map key", func(t *testing.T) {
+		data, err := json.Marshal(map[Point]int{point: 1})
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, `{"1/ab":1}`, string(data))
+
+		var got map[Point]int
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		for k := range got {
+			assert.True(t, point.Equal(k))
+		}
+	})
+
+	t.Run("json unchanged", func(t *testing.T) {
+		data, err := json.Marshal(point)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, `{"hash":"ab","slot":1}`, string(data))
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		var (
+			start  Point
+			points Points
+			fs     = flag.NewFlagSet("test", flag.ContinueOnError)
+		)
+		fs.Var(PointFlag{Point: &start}, "start", "")
+		fs.Var(PointsFlag{Points: &points}, "point", "")
+		if err := fs.Parse([]string{"-start", "origin", "-point", "1/ab", "-point", "2/cd,3/ef"}); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.True(t, Origin.Equal(start))
+		assert.Len(t, points, 3)
+
+		assert.Equal(t, "origin", fs.Lookup("start").Value.String())
+		assert.Equal(t, "1/ab,2/cd,3/ef", fs.Lookup("point").Value.String())
+
+		var (
+			got       Point
+			gotPoints Points
+		)
+		if err := (PointFlag{Point: &got}).Set(PointFlag{Point: &points[0]}.String()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.True(t, points[0].Equal(got))
+		if err := (PointsFlag{Points: &gotPoints}).Set(fs.Lookup("point").Value.String()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, points, gotPoints)
+		assert.Equal(t, "", PointFlag{}.String())
+
+		assert.NotNil(t, fs.Parse([]string{"-point", "bogus"}))
+		assert.NotNil(t, PointFlag{}.Set("origin"))
+		assert.NotNil(t, PointsFlag{}.Set("origin"))
+	})
+}
+
+func TestPoint_Key(t *testing.T) {
+	seen := map[PointKey]int{}
+	seen[PointStruct{Slot: 1, Hash: "ab"}.Point().Key()]++
+	seen[PointStruct{Slot: 1, Hash: "AB", BlockNo: 9}.Point().Key()]++
+	seen[Origin.Key()]++
+	seen[PointString("origin").Point().Key()]++
+
+	assert.Equal(t, map[PointKey]int{
+		PointStruct{Slot: 1, Hash: "ab"}.Point().Key(): 2,
+		Origin.Key(): 2,
+	}, seen)
+	assert.NotEqual(t, PointStruct{Slot: 1, Hash: "ab"}.Point().Key(), PointStruct{Slot: 1, Hash: "cd"}.Point().Key())
+}
+
+func TestPoints_Helpers(t *testing.T) {
+	var (
+		a = PointStruct{Slot: 1, Hash: "ab"}.Point()
+		b = PointStruct{Slot: 3, Hash: "cd"}.Point()
+		c = PointStruct{Slot: 2, Hash: "ef"}.Point()
+	)
+	points := Points{a, Origin, b, PointStruct{Slot: 1, Hash: "ab", BlockNo: 9}.Point(), c, Origin}
+
+	deduped := points.Dedupe()
+	assert.Len(t, deduped, 4)
+	assert.True(t, deduped[0].Equal(a))
+	assert.True(t, deduped[1].Equal(Origin))
+
+	assert.True(t, points.Contains(c))
+	assert.False(t, points.Contains(PointStruct{Slot: 3, Hash: "ab"}.Point()))
+
+	newest, ok := points.Newest()
+	assert.True(t, ok)
+	assert.True(t, newest.Equal(b))
+
+	_, ok = Points{}.Newest()
+	assert.False(t, ok)
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// ProtocolParameters covers the Shelley through Conway protocol parameters.
+// Fields are nil when not applicable to the current era or, within an update
+// proposal, when the parameter is not being changed
+type ProtocolParameters struct {
+	MinFeeCoefficient               *uint64          `json:"minFeeCoefficient,omitempty"               dynamodbav:"minFeeCoefficient,omitempty"`
+	MinFeeConstant                  *uint64          `json:"minFeeConstant,omitempty"                  dynamodbav:"minFeeConstant,omitempty"`
+	MaxBlockBodySize                *uint64          `json:"maxBlockBodySize,omitempty"                dynamodbav:"maxBlockBodySize,omitempty"`
+	MaxBlockHeaderSize              *uint64          `json:"maxBlockHeaderSize,omitempty"              dynamodbav:"maxBlockHeaderSize,omitempty"`
+	MaxTxSize                       *uint64          `json:"maxTxSize,omitempty"                       dynamodbav:"maxTxSize,omitempty"`
+	StakeKeyDeposit                 *num.Int         `json:"stakeKeyDeposit,omitempty"                 dynamodbav:"stakeKeyDeposit,omitempty"`
+	PoolDeposit                     *num.Int         `json:"poolDeposit,omitempty"                     dynamodbav:"poolDeposit,omitempty"`
+	PoolRetirementEpochBound        *uint64          `json:"poolRetirementEpochBound,omitempty"        dynamodbav:"poolRetirementEpochBound,omitempty"`
+	DesiredNumberOfPools            *uint64          `json:"desiredNumberOfPools,omitempty"            dynamodbav:"desiredNumberOfPools,omitempty"`
+	PoolInfluence                   *Ratio           `json:"poolInfluence,omitempty"                   dynamodbav:"poolInfluence,omitempty"`
+	MonetaryExpansion               *Ratio           `json:"monetaryExpansion,omitempty"               dynamodbav:"monetaryExpansion,omitempty"`
+	TreasuryExpansion               *Ratio           `json:"treasuryExpansion,omitempty"               dynamodbav:"treasuryExpansion,omitempty"`
+	DecentralizationParameter       *Ratio           `json:"decentralizationParameter,omitempty"       dynamodbav:"decentralizationParameter,omitempty"` // shelley - alonzo
+	ExtraEntropy                    *string          `json:"extraEntropy,omitempty"                    dynamodbav:"extraEntropy,omitempty"`              // neutral or hex nonce; shelley - alonzo
+	MinUtxoValue                    *num.Int         `json:"minUtxoValue,omitempty"                    dynamodbav:"minUtxoValue,omitempty"`              // shelley - mary
+	MinPoolCost                     *num.Int         `json:"minPoolCost,omitempty"                     dynamodbav:"minPoolCost,omitempty"`
+	ProtocolVersion                 *ProtocolVersion `json:"protocolVersion,omitempty"                 dynamodbav:"protocolVersion,omitempty"`
+	CoinsPerUtxoWord                *num.Int         `json:"coinsPerUtxoWord,omitempty"                dynamodbav:"coinsPerUtxoWord,omitempty"` // alonzo
+	CoinsPerUtxoByte                *num.Int         `json:"coinsPerUtxoByte,omitempty"                dynamodbav:"coinsPerUtxoByte,omitempty"` // babbage
+	CostModels                      CostModels       `json:"costModels,omitempty"                      dynamodbav:"costModels,omitempty"`
+	Prices                          *Prices          `json:"prices,omitempty"                          dynamodbav:"prices,omitempty"`
+	MaxExecutionUnitsPerTransaction *ExecutionUnits  `json:"maxExecutionUnitsPerTransaction,omitempty" dynamodbav:"maxExecutionUnitsPerTransaction,omitempty"`
+	MaxExecutionUnitsPerBlock       *ExecutionUnits  `json:"maxExecutionUnitsPerBlock,omitempty"       dynamodbav:"maxExecutionUnitsPerBlock,omitempty"`
+	MaxValueSize                    *uint64          `json:"maxValueSize,omitempty"                    dynamodbav:"maxValueSize,omitempty"`
+	CollateralPercentage            *uint64          `json:"collateralPercentage,omitempty"            dynamodbav:"collateralPercentage,omitempty"`
+	MaxCollateralInputs             *uint64          `json:"maxCollateralInputs,omitempty"             dynamodbav:"maxCollateralInputs,omitempty"`
+
+	// conway
+	PoolVotingThresholds     *PoolVotingThresholds `json:"poolVotingThresholds,omitempty"     dynamodbav:"poolVotingThresholds,omitempty"`
+	DRepVotingThresholds     *DRepVotingThresholds `json:"drepVotingThresholds,omitempty"     dynamodbav:"drepVotingThresholds,omitempty"`
+	CommitteeMinSize         *uint64               `json:"committeeMinSize,omitempty"         dynamodbav:"committeeMinSize,omitempty"`
+	CommitteeMaxTermLength   *uint64               `json:"committeeMaxTermLength,omitempty"   dynamodbav:"committeeMaxTermLength,omitempty"`   // epochs
+	GovernanceActionLifetime *uint64               `json:"governanceActionLifetime,omitempty" dynamodbav:"governanceActionLifetime,omitempty"` // epochs
+	GovernanceActionDeposit  *num.Int              `json:"governanceActionDeposit,omitempty"  dynamodbav:"governanceActionDeposit,omitempty"`
+	DRepDeposit              *num.Int              `json:"drepDeposit,omitempty"              dynamodbav:"drepDeposit,omitempty"`
+	DRepActivity             *uint64               `json:"drepActivity,omitempty"             dynamodbav:"drepActivity,omitempty"`           // epochs
+	MinFeeReferenceScripts   *Ratio                `json:"minFeeReferenceScripts,omitempty"   dynamodbav:"minFeeReferenceScripts,omitempty"` // lovelace per byte
+}
+
+// CostModels maps plutus language e.g. plutus:v1 to its cost model
+type CostModels map[string]CostModel
+
+// CostModel maps the name of each cost model parameter to its value
+type CostModel map[string]int64
+
+// Prices of execution units, in lovelace per unit
+type Prices struct {
+	Memory Ratio `json:"memory" dynamodbav:"memory"`
+	Steps  Ratio `json:"steps"  dynamodbav:"steps"`
+}
+
+// Update is a protocol parameter update proposal included in a transaction
+type Update struct {
+	Epoch    uint64                        `json:"epoch"              dynamodbav:"epoch"`
+	Proposal map[string]ProtocolParameters `json:"proposal,omitempty" dynamodbav:"proposal,omitempty"` // genesis key hash to proposed parameters
+}
+
+// update allows the default dynamodb decoding to be used
+type update Update
+
+func (u *Update) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, updates were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, u); err != nil {
+			return fmt.Errorf("failed to unmarshal update: %w", err)
+		}
+		return nil
+	}
+
+	var v update
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal update: %w", err)
+	}
+	*u = Update(v)
+	return nil
+}
+
+// Ratio is an exact rational number, encoded by ogmios as "numerator/denominator"
+type Ratio big.Rat
+
+// NewRatio returns the ratio a/b; panics if b is zero
+func NewRatio(a, b int64) Ratio {
+	r := big.NewRat(a, b)
+	return Ratio(*r)
+}
+
+// ParseRatio parses a ratio from either fractional, "3/10", or decimal, "0.3",
+// form
+func ParseRatio(s string) (Ratio, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Ratio{}, fmt.Errorf("failed to parse ratio, %v", s)
+	}
+	return Ratio(*r), nil
+}
+
+func (r Ratio) Rat() *big.Rat {
+	v := big.Rat(r)
+	return new(big.Rat).Set(&v)
+}
+
+func (r Ratio) Num() num.Int {
+	return num.Int(*r.Rat().Num())
+}
+
+func (r Ratio) Denom() num.Int {
+	return num.Int(*r.Rat().Denom())
+}
+
+func (r Ratio) Float64() float64 {
+	f, _ := r.Rat().Float64()
+	return f
+}
+
+func (r Ratio) String() string {
+	return r.Rat().String()
+}
+
+func (r Ratio) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Ratio) UnmarshalJSON(data []byte) error {
+	// ratios are normally strings, but accept plain json numbers too
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("failed to unmarshal ratio: %w", err)
+		}
+	}
+
+	v, err := ParseRatio(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func (r Ratio) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	item.S = aws.String(r.String())
+	return nil
+}
+
+func (r *Ratio) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if aws.BoolValue(item.NULL) {
+		return nil
+	}
+	if item.S == nil {
+		return fmt.Errorf("unable to unmarshal invalid Ratio: S not set")
+	}
+
+	v, err := ParseRatio(aws.StringValue(item.S))
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+const babbageProtocolParametersJSON = `{
+  "minFeeCoefficient": 44,
+  "minFeeConstant": 155381,
+  "maxBlockBodySize": 90112,
+  "maxBlockHeaderSize": 1100,
+  "maxTxSize": 16384,
+  "stakeKeyDeposit": 2000000,
+  "poolDeposit": 500000000,
+  "poolRetirementEpochBound": 18,
+  "desiredNumberOfPools": 500,
+  "poolInfluence": "3/10",
+  "monetaryExpansion": "3/1000",
+  "treasuryExpansion": "1/5",
+  "minPoolCost": 340000000,
+  "coinsPerUtxoByte": 4310,
+  "prices": {"memory": "577/10000", "steps": "721/10000000"},
+  "maxExecutionUnitsPerTransaction": {"memory": 14000000, "steps": 10000000000},
+  "maxExecutionUnitsPerBlock": {"memory": 62000000, "steps": 40000000000},
+  "maxValueSize": 5000,
+  "collateralPercentage": 150,
+  "maxCollateralInputs": 3,
+  "protocolVersion": {"major": 7, "minor": 0},
+  "costModels": {
+    "plutus:v1": {"addInteger-cpu-arguments-intercept": 205665, "addInteger-cpu-arguments-slope": 812},
+    "plutus:v2": {"addInteger-cpu-arguments-intercept": 205665, "verifyEd25519Signature-memory-arguments": 10}
+  }
+}`
+
+func TestProtocolParameters_JSON(t *testing.T) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(babbageProtocolParametersJSON)))
+	decoder.DisallowUnknownFields()
+
+	var params ProtocolParameters
+	err := decoder.Decode(&params)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	assert.EqualValues(t, 44, *params.MinFeeCoefficient)
+	assert.EqualValues(t, 155381, *params.MinFeeConstant)
+	assert.Equal(t, "2000000", params.StakeKeyDeposit.String())
+	assert.Equal(t, "4310", params.CoinsPerUtxoByte.String())
+	assert.Nil(t, params.CoinsPerUtxoWord)
+	assert.Nil(t, params.DecentralizationParameter)
+	assert.Equal(t, big.NewRat(3, 10), params.PoolInfluence.Rat())
+	assert.Equal(t, big.NewRat(577, 10000), params.Prices.Memory.Rat())
+	assert.Equal(t, "721", params.Prices.Steps.Num().String())
+	assert.Equal(t, "10000000", params.Prices.Steps.Denom().String())
+	assert.EqualValues(t, 10000000000, params.MaxExecutionUnitsPerTransaction.Steps)
+	assert.EqualValues(t, 7, params.ProtocolVersion.Major)
+	assert.EqualValues(t, 812, params.CostModels["plutus:v1"]["addInteger-cpu-arguments-slope"])
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.JSONEq(t, babbageProtocolParametersJSON, string(data))
+
+	item, err := dynamodbattribute.Marshal(params)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got ProtocolParameters
+	err = dynamodbattribute.Unmarshal(item, &got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, params, got)
+}
+
+func TestUpdate(t *testing.T) {
+	const updateJSON = `{
+	  "epoch": 365,
+	  "proposal": {
+	    "162f94554ac8c225383a2248c245659eda870eaa82d0ef25fc7dcd82": {"decentralizationParameter": "0/1", "minUtxoValue": 1000000}
+	  }
+	}`
+
+	var body TxBody
+	err := json.Unmarshal([]byte(`{"update": `+updateJSON+`}`), &body)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	proposal, ok := body.Update.Proposal["162f94554ac8c225383a2248c245659eda870eaa82d0ef25fc7dcd82"]
+	assert.True(t, ok)
+	assert.EqualValues(t, 365, body.Update.Epoch)
+	assert.Equal(t, 0, proposal.DecentralizationParameter.Rat().Sign())
+	assert.Equal(t, "1000000", proposal.MinUtxoValue.String())
+	assert.Nil(t, proposal.MinFeeCoefficient)
+
+	item, err := dynamodbattribute.Marshal(body.Update)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got Update
+	err = dynamodbattribute.Unmarshal(item, &got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, *body.Update, got)
+
+	// updates were previously stored as raw json
+	var legacy Update
+	err = dynamodbattribute.Unmarshal(&dynamodb.AttributeValue{B: []byte(updateJSON)}, &legacy)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, *body.Update, legacy)
+}
+
+func TestRatio(t *testing.T) {
+	var r Ratio
+	err := json.Unmarshal([]byte(`0.25`), &r)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, "1/4", r.String())
+	assert.Equal(t, 0.25, r.Float64())
+	assert.Equal(t, NewRatio(1, 4), r)
+
+	err = json.Unmarshal([]byte(`"abc"`), &r)
+	assert.Error(t, err)
+}
@@ -0,0 +1,979 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/cbor"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/address"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/plutusdata"
+)
+
+// script hashes are the blake2b-224 hash of the script prefixed by its type
+const (
+	scriptPrefixNative   = 0
+	scriptPrefixPlutusV1 = 1
+	scriptPrefixPlutusV2 = 2
+	scriptPrefixPlutusV3 = 3
+)
+
+// TxEnvelope is the cardano-cli text envelope used for transaction files
+type TxEnvelope struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	CborHex     string `json:"cborHex"`
+}
+
+// DecodeTx decodes a shelley or later cbor encoded transaction into the shape
+// produced by ogmios. Raw is set to the original bytes, from which RawBody
+// recovers the exact body bytes, and ID is the hash of those body bytes.
+//
+// Where the cbor alone is ambiguous, the decoding assumes the current era:
+// protocol parameter update key 17 is read as CoinsPerUtxoByte, and the time to
+// live populates both TimeToLive and ValidityInterval.InvalidHereafter.
+// Plutus cost models are keyed by parameter index. Byron transactions are not
+// supported.
+func DecodeTx(data []byte) (Tx, error) {
+	var (
+		r                 = cbor.NewReader(data)
+		tx                = Tx{Raw: base64.StdEncoding.EncodeToString(data)}
+		auxiliaryDataHash string
+	)
+
+	err := r.Array(func(i int) error {
+		switch i {
+		case 0:
+			body, err := r.Raw()
+			if err != nil {
+				return err
+			}
+			tx.ID = HashTxBody(body)
+			tx.Body, auxiliaryDataHash, err = decodeTxBody(body)
+			return err
+		case 1:
+			return decodeWitnessSet(r, &tx.Witness)
+		case 2:
+			// alonzo onwards is_valid precedes the auxiliary data
+			b, err := r.Peek()
+			if err != nil {
+				return err
+			}
+			if b == cbor.True || b == cbor.False {
+				valid, err := r.Bool()
+				if err != nil {
+					return fmt.Errorf("failed to decode is_valid: %w", err)
+				}
+				tx.InputSource = "inputs"
+				if !valid {
+					tx.InputSource = "collaterals"
+				}
+				return nil
+			}
+			return decodeTxAuxiliaryData(r, &tx)
+		case 3:
+			return decodeTxAuxiliaryData(r, &tx)
+		default:
+			return fmt.Errorf("unexpected transaction element, %v", i)
+		}
+	})
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to decode tx: %w", err)
+	}
+	if !r.Done() {
+		return Tx{}, fmt.Errorf("failed to decode tx: %v trailing bytes", r.Remaining())
+	}
+
+	if tx.Metadata != nil {
+		tx.Metadata.Hash = auxiliaryDataHash
+	}
+	return tx, nil
+}
+
+// DecodeTxHex decodes a hex encoded cbor transaction
+func DecodeTxHex(s string) (Tx, error) {
+	data, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to decode tx: %w", err)
+	}
+	return DecodeTx(data)
+}
+
+// DecodeTxEnvelope decodes a cardano-cli transaction text envelope
+func DecodeTxEnvelope(data []byte) (Tx, error) {
+	var envelope TxEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Tx{}, fmt.Errorf("failed to decode tx envelope: %w", err)
+	}
+	if !strings.Contains(envelope.Type, "Tx") {
+		return Tx{}, fmt.Errorf("failed to decode tx envelope: unexpected type, %v", envelope.Type)
+	}
+	return DecodeTxHex(envelope.CborHex)
+}
+
+// DecodeRaw decodes the serialized transaction held by Raw
+func (t Tx) DecodeRaw() (Tx, error) {
+	data, err := t.rawBytes()
+	if err != nil {
+		return Tx{}, err
+	}
+	return DecodeTx(data)
+}
+
+// RawBody returns the original cbor bytes of the transaction body from Raw
+func (t Tx) RawBody() ([]byte, error) {
+	data, err := t.rawBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := txBody(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw tx, %v: %w", t.ID, err)
+	}
+	return body, nil
+}
+
+// txBody returns the body bytes of a cbor encoded transaction
+func txBody(data []byte) ([]byte, error) {
+	r := cbor.NewReader(data)
+	if _, _, err := r.Expect(cbor.MajorArray); err != nil {
+		return nil, err
+	}
+	return r.Raw()
+}
+
+func (t Tx) rawBytes() ([]byte, error) {
+	if t.Raw == "" {
+		return nil, fmt.Errorf("raw transaction not available for tx, %v", t.ID)
+	}
+	data, err := base64.StdEncoding.DecodeString(t.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw tx, %v: %w", t.ID, err)
+	}
+	return data, nil
+}
+
+func decodeTxAuxiliaryData(r *cbor.Reader, tx *Tx) error {
+	if r.Null() {
+		return nil
+	}
+	aux, err := decodeAuxiliaryData(r)
+	if err != nil {
+		return fmt.Errorf("failed to decode auxiliary data: %w", err)
+	}
+	tx.Metadata = aux
+	return nil
+}
+
+// decodeTxBody returns the body along with the auxiliary data hash, which
+// ogmios reports as part of the auxiliary data
+func decodeTxBody(data []byte) (TxBody, string, error) {
+	var (
+		r                 = cbor.NewReader(data)
+		body              TxBody
+		auxiliaryDataHash string
+	)
+
+	err := r.Map(func(int) error {
+		key, err := r.Uint()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case 0:
+			body.Inputs, err = decodeTxIns(r)
+		case 1:
+			err = r.Array(func(int) error {
+				out, err := decodeTxOut(r)
+				body.Outputs = append(body.Outputs, out)
+				return err
+			})
+		case 2:
+			body.Fee, err = decodeInt(r)
+		case 3:
+			var ttl uint64
+			if ttl, err = r.Uint(); err == nil {
+				body.TimeToLive = int64(ttl)
+				body.ValidityInterval.InvalidHereafter = ttl
+			}
+		case 4:
+			err = r.Array(func(int) error {
+				c, err := decodeCertificate(r)
+				body.Certificates = append(body.Certificates, c)
+				return err
+			})
+		case 5:
+			body.Withdrawals = map[string]int64{}
+			err = r.Map(func(int) error {
+				account, err := decodeRewardAccount(r)
+				if err != nil {
+					return err
+				}
+				amount, err := decodeInt64(r)
+				body.Withdrawals[account] = amount
+				return err
+			})
+		case 6:
+			body.Update, err = decodeUpdate(r)
+		case 7:
+			auxiliaryDataHash, err = decodeHex(r)
+		case 8:
+			body.ValidityInterval.InvalidBefore, err = r.Uint()
+		case 9:
+			var assets map[AssetID]num.Int
+			if assets, err = decodeMultiAsset(r); err == nil {
+				body.Mint = &Value{Assets: assets}
+			}
+		case 11:
+			body.ScriptIntegrityHash, err = decodeHex(r)
+		case 13:
+			body.Collaterals, err = decodeTxIns(r)
+		case 14:
+			err = r.Array(func(int) error {
+				signer, err := decodeHex(r)
+				body.RequiredExtraSignatures = append(body.RequiredExtraSignatures, signer)
+				return err
+			})
+		case 15:
+			var network uint64
+			if network, err = r.Uint(); err == nil {
+				body.Network = json.RawMessage(`"testnet"`)
+				if network == uint64(address.Mainnet) {
+					body.Network = json.RawMessage(`"mainnet"`)
+				}
+			}
+		case 16:
+			var out TxOut
+			if out, err = decodeTxOut(r); err == nil {
+				body.CollateralReturn = &out
+			}
+		case 17:
+			var total int64
+			if total, err = decodeInt64(r); err == nil {
+				body.TotalCollateral = &total
+			}
+		case 18:
+			body.References, err = decodeTxIns(r)
+		case 19:
+			body.Votes, err = decodeVotingProcedures(r)
+		case 20:
+			err = r.Array(func(int) error {
+				p, err := decodeProposalProcedure(r)
+				body.Proposals = append(body.Proposals, p)
+				return err
+			})
+		case 21:
+			var v num.Int
+			if v, err = decodeInt(r); err == nil {
+				body.TreasuryValue = &v
+			}
+		case 22:
+			var v num.Int
+			if v, err = decodeInt(r); err == nil {
+				body.Donation = &v
+			}
+		default:
+			err = r.Skip()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode tx body field %v: %w", key, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return TxBody{}, "", err
+	}
+	return body, auxiliaryDataHash, nil
+}
+
+func decodeTxIns(r *cbor.Reader) ([]TxIn, error) {
+	var ins []TxIn
+	err := r.Array(func(int) error {
+		in, err := decodeTxIn(r)
+		ins = append(ins, in)
+		return err
+	})
+	return ins, err
+}
+
+func decodeTxIn(r *cbor.Reader) (TxIn, error) {
+	var in TxIn
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			in.TxHash, err = decodeHex(r)
+		case 1:
+			var index uint64
+			index, err = r.Uint()
+			in.Index = int(index)
+		default:
+			err = fmt.Errorf("unexpected tx input element, %v", i)
+		}
+		return err
+	})
+	return in, err
+}
+
+// decodeTxOut reads either the legacy array or the babbage map encoding
+func decodeTxOut(r *cbor.Reader) (TxOut, error) {
+	var out TxOut
+
+	major, err := r.PeekMajor()
+	if err != nil {
+		return TxOut{}, err
+	}
+	if major == cbor.MajorArray {
+		err := r.Array(func(i int) (err error) {
+			switch i {
+			case 0:
+				out.Address, err = decodeAddress(r)
+			case 1:
+				out.Value, err = decodeValue(r)
+			case 2:
+				out.DatumHash, err = decodeHex(r)
+			default:
+				err = fmt.Errorf("unexpected tx output element, %v", i)
+			}
+			return err
+		})
+		return out, err
+	}
+
+	err = r.Map(func(int) error {
+		key, err := r.Uint()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case 0:
+			out.Address, err = decodeAddress(r)
+		case 1:
+			out.Value, err = decodeValue(r)
+		case 2:
+			err = r.Array(func(i int) (err error) {
+				switch i {
+				case 0:
+					var kind uint64
+					if kind, err = r.Uint(); err == nil && kind > 1 {
+						err = fmt.Errorf("unknown datum option, %v", kind)
+					}
+				case 1:
+					if major, _ := r.PeekMajor(); major == cbor.MajorTag {
+						out.Datum, err = decodeEncodedCBORHex(r)
+					} else {
+						out.DatumHash, err = decodeHex(r)
+					}
+				}
+				return err
+			})
+		case 3:
+			var data []byte
+			if data, err = decodeEncodedCBOR(r); err == nil {
+				var script Script
+				if script, _, err = decodeScriptRef(cbor.NewReader(data)); err == nil {
+					out.Script = &script
+				}
+			}
+		default:
+			err = r.Skip()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode tx output field %v: %w", key, err)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func decodeAddress(r *cbor.Reader) (string, error) {
+	data, err := r.Bytes()
+	if err != nil {
+		return "", err
+	}
+	addr, err := address.FromBytes(data)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+func decodeRewardAccount(r *cbor.Reader) (string, error) {
+	s, err := decodeAddress(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode reward account: %w", err)
+	}
+	return s, nil
+}
+
+func decodeValue(r *cbor.Reader) (Value, error) {
+	major, err := r.PeekMajor()
+	if err != nil {
+		return Value{}, err
+	}
+	if major != cbor.MajorArray {
+		coins, err := decodeInt(r)
+		return Value{Coins: coins}, err
+	}
+
+	var v Value
+	err = r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			v.Coins, err = decodeInt(r)
+		case 1:
+			v.Assets, err = decodeMultiAsset(r)
+		default:
+			err = fmt.Errorf("unexpected value element, %v", i)
+		}
+		return err
+	})
+	return v, err
+}
+
+func decodeMultiAsset(r *cbor.Reader) (map[AssetID]num.Int, error) {
+	assets := map[AssetID]num.Int{}
+	err := r.Map(func(int) error {
+		policyID, err := decodeHex(r)
+		if err != nil {
+			return err
+		}
+		return r.Map(func(int) error {
+			assetName, err := decodeHex(r)
+			if err != nil {
+				return err
+			}
+			quantity, err := decodeInt(r)
+			if err != nil {
+				return err
+			}
+			assetID := AssetID(policyID)
+			if assetName != "" {
+				assetID = AssetID(policyID + "." + assetName)
+			}
+			assets[assetID] = quantity
+			return nil
+		})
+	})
+	return assets, err
+}
+
+// decodeScriptRef reads a tagged script, [type, script], and returns it along
+// with its hash
+func decodeScriptRef(r *cbor.Reader) (Script, string, error) {
+	var (
+		script Script
+		hash   string
+		kind   uint64
+	)
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			kind, err = r.Uint()
+		case 1:
+			script, hash, err = decodeScript(r, kind)
+		default:
+			err = fmt.Errorf("unexpected script element, %v", i)
+		}
+		return err
+	})
+	return script, hash, err
+}
+
+// decodeScript reads a script of the given kind, as used by script hash
+// prefixes, and returns it along with its hash
+func decodeScript(r *cbor.Reader, kind uint64) (Script, string, error) {
+	if kind == scriptPrefixNative {
+		data, err := r.Raw()
+		if err != nil {
+			return Script{}, "", err
+		}
+		native, err := decodeNativeScript(cbor.NewReader(data))
+		if err != nil {
+			return Script{}, "", err
+		}
+		return Script{Native: &native}, scriptHash(kind, data), nil
+	}
+
+	data, err := r.Bytes()
+	if err != nil {
+		return Script{}, "", err
+	}
+
+	s := base64.StdEncoding.EncodeToString(data)
+	switch kind {
+	case scriptPrefixPlutusV1:
+		return Script{PlutusV1: s}, scriptHash(kind, data), nil
+	case scriptPrefixPlutusV2:
+		return Script{PlutusV2: s}, scriptHash(kind, data), nil
+	case scriptPrefixPlutusV3:
+		return Script{PlutusV3: s}, scriptHash(kind, data), nil
+	default:
+		return Script{}, "", fmt.Errorf("unknown script type, %v", kind)
+	}
+}
+
+func scriptHash(prefix uint64, data []byte) string {
+	h, _ := blake2b.New(28, nil)
+	h.Write([]byte{byte(prefix)})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func decodeNativeScript(r *cbor.Reader) (NativeScript, error) {
+	var (
+		script NativeScript
+		kind   uint64
+	)
+
+	scripts := func() error {
+		return r.Array(func(int) error {
+			s, err := decodeNativeScript(r)
+			script.Scripts = append(script.Scripts, s)
+			return err
+		})
+	}
+
+	err := r.Array(func(i int) (err error) {
+		if i == 0 {
+			kind, err = r.Uint()
+			return err
+		}
+
+		switch {
+		case kind == 0 && i == 1:
+			script.Type = NativeScriptTypeSignature
+			script.KeyHash, err = decodeHex(r)
+		case kind == 1 && i == 1:
+			script.Type = NativeScriptTypeAll
+			err = scripts()
+		case kind == 2 && i == 1:
+			script.Type = NativeScriptTypeAny
+			err = scripts()
+		case kind == 3 && i == 1:
+			var n uint64
+			n, err = r.Uint()
+			script.Type, script.N = NativeScriptTypeNOf, int(n)
+		case kind == 3 && i == 2:
+			err = scripts()
+		case kind == 4 && i == 1:
+			script.Type = NativeScriptTypeStartsAt
+			script.Slot, err = r.Uint()
+		case kind == 5 && i == 1:
+			script.Type = NativeScriptTypeExpiresAt
+			script.Slot, err = r.Uint()
+		default:
+			err = fmt.Errorf("unexpected native script element, %v, for type %v", i, kind)
+		}
+		return err
+	})
+	if err != nil {
+		return NativeScript{}, fmt.Errorf("failed to decode native script: %w", err)
+	}
+	return script, nil
+}
+
+func decodeWitnessSet(r *cbor.Reader, witness *Witness) error {
+	addScript := func(kind uint64) error {
+		return r.Array(func(int) error {
+			script, hash, err := decodeScript(r, kind)
+			if err != nil {
+				return err
+			}
+			if witness.Scripts == nil {
+				witness.Scripts = Scripts{}
+			}
+			witness.Scripts[hash] = script
+			return nil
+		})
+	}
+
+	return r.Map(func(int) error {
+		key, err := r.Uint()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case 0:
+			witness.Signatures = map[string]string{}
+			err = r.Array(func(int) error {
+				var key, signature string
+				err := r.Array(func(i int) (err error) {
+					switch i {
+					case 0:
+						key, err = decodeHex(r)
+					case 1:
+						signature, err = decodeBase64(r)
+					default:
+						err = fmt.Errorf("unexpected vkey witness element, %v", i)
+					}
+					return err
+				})
+				witness.Signatures[key] = signature
+				return err
+			})
+		case 1:
+			err = addScript(scriptPrefixNative)
+		case 2:
+			err = r.Array(func(int) error {
+				var bootstrap BootstrapWitness
+				err := r.Array(func(i int) (err error) {
+					switch i {
+					case 0:
+						bootstrap.Key, err = decodeHex(r)
+					case 1:
+						bootstrap.Signature, err = decodeBase64(r)
+					case 2:
+						bootstrap.ChainCode, err = decodeHex(r)
+					case 3:
+						bootstrap.AddressAttributes, err = decodeBase64(r)
+					default:
+						err = fmt.Errorf("unexpected bootstrap witness element, %v", i)
+					}
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				data, err := json.Marshal(bootstrap)
+				witness.Bootstrap = append(witness.Bootstrap, data)
+				return err
+			})
+		case 3:
+			err = addScript(scriptPrefixPlutusV1)
+		case 4:
+			witness.Datums = Datums{}
+			err = r.Array(func(int) error {
+				data, err := r.Raw()
+				witness.Datums[plutusdata.Hash(data)] = hex.EncodeToString(data)
+				return err
+			})
+		case 5:
+			witness.Redeemers, err = decodeRedeemers(r)
+		case 6:
+			err = addScript(scriptPrefixPlutusV2)
+		case 7:
+			err = addScript(scriptPrefixPlutusV3)
+		default:
+			err = r.Skip()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode witness field %v: %w", key, err)
+		}
+		return nil
+	})
+}
+
+var redeemerPurposes = []RedeemerPurpose{
+	RedeemerPurposeSpend,
+	RedeemerPurposeMint,
+	RedeemerPurposeCertificate,
+	RedeemerPurposeWithdrawal,
+	RedeemerPurposeVote,
+	RedeemerPurposePropose,
+}
+
+// decodeRedeemers reads either the legacy array of redeemers or the conway
+// map keyed by [tag, index]
+func decodeRedeemers(r *cbor.Reader) (Redeemers, error) {
+	redeemers := Redeemers{}
+
+	pointer := func(tag, index uint64) (RedeemerPointer, error) {
+		if tag >= uint64(len(redeemerPurposes)) {
+			return "", fmt.Errorf("unknown redeemer tag, %v", tag)
+		}
+		return NewRedeemerPointer(redeemerPurposes[tag], int(index)), nil
+	}
+
+	major, err := r.PeekMajor()
+	if err != nil {
+		return nil, err
+	}
+
+	if major == cbor.MajorArray {
+		err := r.Array(func(int) error {
+			var (
+				tag, index uint64
+				redeemer   Redeemer
+			)
+			err := r.Array(func(i int) (err error) {
+				switch i {
+				case 0:
+					tag, err = r.Uint()
+				case 1:
+					index, err = r.Uint()
+				case 2:
+					redeemer.Redeemer, err = decodeRawBase64(r)
+				case 3:
+					redeemer.ExecutionUnits, err = decodeExecutionUnits(r)
+				default:
+					err = fmt.Errorf("unexpected redeemer element, %v", i)
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			p, err := pointer(tag, index)
+			redeemers[p] = redeemer
+			return err
+		})
+		return redeemers, err
+	}
+
+	err = r.Map(func(int) error {
+		var tag, index uint64
+		err := r.Array(func(i int) (err error) {
+			switch i {
+			case 0:
+				tag, err = r.Uint()
+			case 1:
+				index, err = r.Uint()
+			default:
+				err = fmt.Errorf("unexpected redeemer key element, %v", i)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		var redeemer Redeemer
+		err = r.Array(func(i int) (err error) {
+			switch i {
+			case 0:
+				redeemer.Redeemer, err = decodeRawBase64(r)
+			case 1:
+				redeemer.ExecutionUnits, err = decodeExecutionUnits(r)
+			default:
+				err = fmt.Errorf("unexpected redeemer value element, %v", i)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		p, err := pointer(tag, index)
+		redeemers[p] = redeemer
+		return err
+	})
+	return redeemers, err
+}
+
+func decodeExecutionUnits(r *cbor.Reader) (ExecutionUnits, error) {
+	var units ExecutionUnits
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			units.Memory, err = r.Uint()
+		case 1:
+			units.Steps, err = r.Uint()
+		default:
+			err = fmt.Errorf("unexpected execution units element, %v", i)
+		}
+		return err
+	})
+	return units, err
+}
+
+// decodeAuxiliaryData reads the shelley metadata map, the allegra
+// [metadata, scripts] pair or the alonzo tagged map
+func decodeAuxiliaryData(r *cbor.Reader) (*AuxiliaryData, error) {
+	body := &AuxiliaryDataBody{}
+
+	major, err := r.PeekMajor()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cbor.MajorMap:
+		body.Blob, err = decodeMetadata(r)
+
+	case cbor.MajorArray:
+		err = r.Array(func(i int) (err error) {
+			switch i {
+			case 0:
+				body.Blob, err = decodeMetadata(r)
+			case 1:
+				err = r.Array(func(int) error {
+					script, _, err := decodeScript(r, scriptPrefixNative)
+					body.Scripts = append(body.Scripts, script)
+					return err
+				})
+			default:
+				err = fmt.Errorf("unexpected auxiliary data element, %v", i)
+			}
+			return err
+		})
+
+	case cbor.MajorTag:
+		if _, err := r.Tag(); err != nil {
+			return nil, err
+		}
+		err = r.Map(func(int) error {
+			key, err := r.Uint()
+			if err != nil {
+				return err
+			}
+			if key == 0 {
+				body.Blob, err = decodeMetadata(r)
+				return err
+			}
+			return r.Array(func(int) error {
+				script, _, err := decodeScript(r, key-1)
+				body.Scripts = append(body.Scripts, script)
+				return err
+			})
+		})
+
+	default:
+		err = fmt.Errorf("unexpected auxiliary data major type, %v", major)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &AuxiliaryData{Body: body}, nil
+}
+
+func decodeMetadata(r *cbor.Reader) (Metadata, error) {
+	metadata := Metadata{}
+	err := r.Map(func(int) error {
+		label, err := r.Uint()
+		if err != nil {
+			return err
+		}
+		v, err := decodeMetadatum(r)
+		metadata[strconv.FormatUint(label, 10)] = v
+		return err
+	})
+	return metadata, err
+}
+
+func decodeMetadatum(r *cbor.Reader) (Metadatum, error) {
+	major, err := r.PeekMajor()
+	if err != nil {
+		return Metadatum{}, err
+	}
+
+	switch major {
+	case cbor.MajorUint, cbor.MajorNegInt, cbor.MajorTag:
+		v, err := decodeInt(r)
+		return Metadatum{Type: MetadatumTypeInt, Int: v}, err
+	case cbor.MajorBytes:
+		v, err := r.Bytes()
+		return Metadatum{Type: MetadatumTypeBytes, Bytes: v}, err
+	case cbor.MajorText:
+		v, err := r.Text()
+		return Metadatum{Type: MetadatumTypeString, String: v}, err
+	case cbor.MajorArray:
+		m := Metadatum{Type: MetadatumTypeList, List: []Metadatum{}}
+		err := r.Array(func(int) error {
+			item, err := decodeMetadatum(r)
+			m.List = append(m.List, item)
+			return err
+		})
+		return m, err
+	case cbor.MajorMap:
+		m := Metadatum{Type: MetadatumTypeMap, Map: []MetadatumPair{}}
+		err := r.Map(func(int) error {
+			key, err := decodeMetadatum(r)
+			if err != nil {
+				return err
+			}
+			value, err := decodeMetadatum(r)
+			m.Map = append(m.Map, MetadatumPair{Key: key, Value: value})
+			return err
+		})
+		return m, err
+	default:
+		return Metadatum{}, fmt.Errorf("unexpected metadatum major type, %v", major)
+	}
+}
+
+func decodeHex(r *cbor.Reader) (string, error) {
+	data, err := r.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// decodeBase64 reads a byte string and returns it base64 encoded, as ogmios
+// does for signatures
+func decodeBase64(r *cbor.Reader) (string, error) {
+	data, err := r.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decodeRawBase64 returns the base64 encoding of the next item's original
+// bytes, as ogmios does for redeemers
+func decodeRawBase64(r *cbor.Reader) (string, error) {
+	data, err := r.Raw()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decodeEncodedCBOR reads the bytes wrapped by tag 24
+func decodeEncodedCBOR(r *cbor.Reader) ([]byte, error) {
+	tag, err := r.Tag()
+	if err != nil {
+		return nil, err
+	}
+	if tag != cbor.TagEncodedCBOR {
+		return nil, fmt.Errorf("got tag %v; want %v", tag, cbor.TagEncodedCBOR)
+	}
+	return r.Bytes()
+}
+
+func decodeEncodedCBORHex(r *cbor.Reader) (string, error) {
+	data, err := decodeEncodedCBOR(r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// decodeInt reads an integer of any size, including bignums
+func decodeInt(r *cbor.Reader) (num.Int, error) {
+	v, err := r.Int()
+	if err != nil {
+		return num.Int{}, err
+	}
+	return num.Int(*v), nil
+}
+
+func decodeInt64(r *cbor.Reader) (int64, error) {
+	v, err := decodeInt(r)
+	if err != nil {
+		return 0, err
+	}
+	return v.CheckedInt64()
+}
@@ -0,0 +1,903 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/bech32"
+	"github.com/SundaeSwap-finance/ogmigo/internal/cbor"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// decodeCertificate reads a shelley through conway certificate. Credentials
+// are reported as their hex encoded hash and pools by their bech32 id
+func decodeCertificate(r *cbor.Reader) (Certificate, error) {
+	var (
+		c    Certificate
+		kind uint64
+	)
+
+	fields := []func() error{}
+	credential := func(dst *string) func() error {
+		return func() (err error) { *dst, _, err = decodeCredential(r); return err }
+	}
+	pool := func(dst *string) func() error {
+		return func() (err error) { *dst, err = decodePoolID(r); return err }
+	}
+	drep := func(dst **DRep) func() error {
+		return func() error {
+			v, err := decodeDRep(r)
+			*dst = &v
+			return err
+		}
+	}
+	deposit := func(dst *num.Int) func() error {
+		return func() (err error) { *dst, err = decodeInt(r); return err }
+	}
+	depositPtr := func(dst **num.Int) func() error {
+		return func() error {
+			v, err := decodeInt(r)
+			*dst = &v
+			return err
+		}
+	}
+	anchor := func(dst **Anchor) func() error {
+		return func() (err error) { *dst, err = decodeOptionalAnchor(r); return err }
+	}
+
+	err := r.Array(func(i int) (err error) {
+		if i == 0 {
+			if kind, err = r.Uint(); err != nil {
+				return err
+			}
+
+			switch kind {
+			case 0:
+				fields = append(fields, credential(&c.StakeKeyRegistration))
+			case 1:
+				fields = append(fields, credential(&c.StakeKeyDeregistration))
+			case 2:
+				c.StakeDelegation = &StakeDelegation{}
+				fields = append(fields, credential(&c.StakeDelegation.Delegator), pool(&c.StakeDelegation.Delegatee))
+			case 3:
+				c.PoolRegistration = &PoolParameters{}
+				fields = append(fields, poolParameterFields(r, c.PoolRegistration)...)
+			case 4:
+				c.PoolRetirement = &PoolRetirement{}
+				fields = append(fields,
+					pool(&c.PoolRetirement.PoolID),
+					func() (err error) { c.PoolRetirement.RetirementEpoch, err = r.Uint(); return err },
+				)
+			case 5:
+				g := &GenesisDelegation{}
+				c.GenesisDelegation = g
+				fields = append(fields,
+					func() (err error) { g.VerificationKeyHash, err = decodeHex(r); return err },
+					func() (err error) { g.DelegateKeyHash, err = decodeHex(r); return err },
+					func() (err error) { g.VRFVerificationKeyHash, err = decodeHex(r); return err },
+				)
+			case 6:
+				fields = append(fields, func() (err error) {
+					c.MoveInstantaneousRewards, err = decodeMoveInstantaneousReward(r)
+					return err
+				})
+			case 7, 8:
+				d := &StakeCredentialDeposit{}
+				if kind == 7 {
+					c.StakeCredentialRegistration = d
+				} else {
+					c.StakeCredentialDeregistration = d
+				}
+				fields = append(fields, credential(&d.Credential), deposit(&d.Deposit))
+			case 9:
+				d := &Delegation{}
+				c.VoteDelegation = d
+				fields = append(fields, credential(&d.Delegator), drep(&d.DRep))
+			case 10:
+				d := &Delegation{}
+				c.StakeVoteDelegation = d
+				fields = append(fields, credential(&d.Delegator), pool(&d.Pool), drep(&d.DRep))
+			case 11:
+				d := &Delegation{}
+				c.StakeRegistrationDelegation = d
+				fields = append(fields, credential(&d.Delegator), pool(&d.Pool), depositPtr(&d.Deposit))
+			case 12:
+				d := &Delegation{}
+				c.VoteRegistrationDelegation = d
+				fields = append(fields, credential(&d.Delegator), drep(&d.DRep), depositPtr(&d.Deposit))
+			case 13:
+				d := &Delegation{}
+				c.StakeVoteRegistrationDelegation = d
+				fields = append(fields, credential(&d.Delegator), pool(&d.Pool), drep(&d.DRep), depositPtr(&d.Deposit))
+			case 14:
+				a := &CommitteeAuthorization{}
+				c.CommitteeHotKeyAuthorization = a
+				fields = append(fields, credential(&a.ColdCredential), credential(&a.HotCredential))
+			case 15:
+				a := &CommitteeResignation{}
+				c.CommitteeColdKeyResignation = a
+				fields = append(fields, credential(&a.ColdCredential), anchor(&a.Anchor))
+			case 16:
+				d := &DRepRegistration{}
+				c.DRepRegistration = d
+				fields = append(fields, credential(&d.Credential), depositPtr(&d.Deposit), anchor(&d.Anchor))
+			case 17:
+				d := &DRepRegistration{}
+				c.DRepDeregistration = d
+				fields = append(fields, credential(&d.Credential), depositPtr(&d.Deposit))
+			case 18:
+				d := &DRepRegistration{}
+				c.DRepUpdate = d
+				fields = append(fields, credential(&d.Credential), anchor(&d.Anchor))
+			default:
+				return fmt.Errorf("unknown certificate type, %v", kind)
+			}
+			return nil
+		}
+
+		if i > len(fields) {
+			return fmt.Errorf("unexpected certificate element, %v, for type %v", i, kind)
+		}
+		return fields[i-1]()
+	})
+	if err != nil {
+		return Certificate{}, fmt.Errorf("failed to decode certificate: %w", err)
+	}
+	return c, nil
+}
+
+func poolParameterFields(r *cbor.Reader, p *PoolParameters) []func() error {
+	return []func() error{
+		func() (err error) { p.ID, err = decodePoolID(r); return err },
+		func() (err error) { p.VRF, err = decodeHex(r); return err },
+		func() (err error) { p.Pledge, err = decodeInt(r); return err },
+		func() (err error) { p.Cost, err = decodeInt(r); return err },
+		func() (err error) { p.Margin, err = decodeRatio(r); return err },
+		func() (err error) { p.RewardAccount, err = decodeRewardAccount(r); return err },
+		func() error {
+			return r.Array(func(int) error {
+				owner, err := decodeHex(r)
+				p.Owners = append(p.Owners, owner)
+				return err
+			})
+		},
+		func() error {
+			return r.Array(func(int) error {
+				relay, err := decodeRelay(r)
+				p.Relays = append(p.Relays, relay)
+				return err
+			})
+		},
+		func() error {
+			if r.Null() {
+				return nil
+			}
+			anchor, err := decodeAnchor(r)
+			p.Metadata = &PoolMetadata{URL: anchor.URL, Hash: anchor.Hash}
+			return err
+		},
+	}
+}
+
+// decodeRelay reads a single host address, single host name or multi host
+// name relay
+func decodeRelay(r *cbor.Reader) (Relay, error) {
+	var (
+		relay Relay
+		kind  uint64
+	)
+
+	port := func() error {
+		if r.Null() {
+			return nil
+		}
+		v, err := r.Uint()
+		if err == nil && v > 0xffff {
+			err = fmt.Errorf("invalid relay port, %v", v)
+		}
+		p := uint16(v)
+		relay.Port = &p
+		return err
+	}
+	ip := func(dst **string, size int) error {
+		if r.Null() {
+			return nil
+		}
+		data, err := r.Bytes()
+		if err != nil {
+			return err
+		}
+		if len(data) != size {
+			return fmt.Errorf("invalid relay ip length, %v", len(data))
+		}
+		if size == net.IPv6len {
+			// the ledger serializes ipv6 addresses as four little endian words
+			for i := 0; i < size; i += 4 {
+				data[i], data[i+1], data[i+2], data[i+3] = data[i+3], data[i+2], data[i+1], data[i]
+			}
+		}
+		s := net.IP(data).String()
+		*dst = &s
+		return nil
+	}
+	hostname := func() error {
+		s, err := r.Text()
+		relay.Hostname = &s
+		return err
+	}
+
+	err := r.Array(func(i int) (err error) {
+		switch {
+		case i == 0:
+			kind, err = r.Uint()
+		case kind == 0 && i == 1, kind == 1 && i == 1:
+			err = port()
+		case kind == 0 && i == 2:
+			err = ip(&relay.IPv4, net.IPv4len)
+		case kind == 0 && i == 3:
+			err = ip(&relay.IPv6, net.IPv6len)
+		case kind == 1 && i == 2, kind == 2 && i == 1:
+			err = hostname()
+		default:
+			err = fmt.Errorf("unexpected relay element, %v, for type %v", i, kind)
+		}
+		return err
+	})
+	return relay, err
+}
+
+func decodeMoveInstantaneousReward(r *cbor.Reader) (*MoveInstantaneousReward, error) {
+	mir := &MoveInstantaneousReward{}
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			var pot uint64
+			if pot, err = r.Uint(); err != nil {
+				return err
+			}
+			switch pot {
+			case 0:
+				mir.Pot = "reserves"
+			case 1:
+				mir.Pot = "treasury"
+			default:
+				return fmt.Errorf("unknown pot, %v", pot)
+			}
+		case 1:
+			if major, _ := r.PeekMajor(); major != cbor.MajorMap {
+				v, err := decodeInt(r)
+				mir.Value = &v
+				return err
+			}
+			mir.Rewards = map[string]num.Int{}
+			err = r.Map(func(int) error {
+				credential, _, err := decodeCredential(r)
+				if err != nil {
+					return err
+				}
+				v, err := decodeInt(r)
+				mir.Rewards[credential] = v
+				return err
+			})
+		default:
+			err = fmt.Errorf("unexpected move instantaneous reward element, %v", i)
+		}
+		return err
+	})
+	return mir, err
+}
+
+// decodeCredential reads [0, key hash] or [1, script hash]
+func decodeCredential(r *cbor.Reader) (hash string, script bool, err error) {
+	err = r.Array(func(i int) error {
+		switch i {
+		case 0:
+			kind, err := r.Uint()
+			if err != nil {
+				return err
+			}
+			if kind > 1 {
+				return fmt.Errorf("unknown credential type, %v", kind)
+			}
+			script = kind == 1
+			return nil
+		case 1:
+			hash, err = decodeHex(r)
+			return err
+		default:
+			return fmt.Errorf("unexpected credential element, %v", i)
+		}
+	})
+	return hash, script, err
+}
+
+func decodePoolID(r *cbor.Reader) (string, error) {
+	data, err := r.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode("pool", data)
+}
+
+func decodeDRep(r *cbor.Reader) (DRep, error) {
+	var drep DRep
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			var kind uint64
+			if kind, err = r.Uint(); err != nil {
+				return err
+			}
+			switch kind {
+			case 0:
+				drep.Type = DRepTypeKey
+			case 1:
+				drep.Type = DRepTypeScript
+			case 2:
+				drep.Type = DRepTypeAbstain
+			case 3:
+				drep.Type = DRepTypeNoConfidence
+			default:
+				return fmt.Errorf("unknown drep type, %v", kind)
+			}
+		case 1:
+			drep.ID, err = decodeHex(r)
+		default:
+			err = fmt.Errorf("unexpected drep element, %v", i)
+		}
+		return err
+	})
+	return drep, err
+}
+
+func decodeAnchor(r *cbor.Reader) (Anchor, error) {
+	var anchor Anchor
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			anchor.URL, err = r.Text()
+		case 1:
+			anchor.Hash, err = decodeHex(r)
+		default:
+			err = fmt.Errorf("unexpected anchor element, %v", i)
+		}
+		return err
+	})
+	return anchor, err
+}
+
+func decodeOptionalAnchor(r *cbor.Reader) (*Anchor, error) {
+	if r.Null() {
+		return nil, nil
+	}
+	anchor, err := decodeAnchor(r)
+	return &anchor, err
+}
+
+// decodeRatio reads a tag 30 rational, [numerator, denominator]
+func decodeRatio(r *cbor.Reader) (Ratio, error) {
+	tag, err := r.Tag()
+	if err != nil {
+		return Ratio{}, err
+	}
+	if tag != cbor.TagRational {
+		return Ratio{}, fmt.Errorf("got tag %v; want %v", tag, cbor.TagRational)
+	}
+
+	var n, d num.Int
+	err = r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			n, err = decodeInt(r)
+		case 1:
+			d, err = decodeInt(r)
+		default:
+			err = fmt.Errorf("unexpected rational element, %v", i)
+		}
+		return err
+	})
+	if err != nil {
+		return Ratio{}, err
+	}
+	if d.IsZero() {
+		return Ratio{}, fmt.Errorf("invalid rational: zero denominator")
+	}
+	return ParseRatio(n.String() + "/" + d.String())
+}
+
+func decodeGovActionID(r *cbor.Reader) (GovActionID, error) {
+	var id GovActionID
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			id.TxHash, err = decodeHex(r)
+		case 1:
+			var index uint64
+			index, err = r.Uint()
+			id.Index = int(index)
+		default:
+			err = fmt.Errorf("unexpected governance action id element, %v", i)
+		}
+		return err
+	})
+	return id, err
+}
+
+func decodeOptionalGovActionID(r *cbor.Reader) (*GovActionID, error) {
+	if r.Null() {
+		return nil, nil
+	}
+	id, err := decodeGovActionID(r)
+	return &id, err
+}
+
+func decodeOptionalHex(r *cbor.Reader) (string, error) {
+	if r.Null() {
+		return "", nil
+	}
+	return decodeHex(r)
+}
+
+// decodeVotingProcedures flattens the voter to action to vote map
+func decodeVotingProcedures(r *cbor.Reader) ([]VotingProcedure, error) {
+	var votes []VotingProcedure
+	err := r.Map(func(int) error {
+		var voter Voter
+		err := r.Array(func(i int) (err error) {
+			switch i {
+			case 0:
+				var kind uint64
+				if kind, err = r.Uint(); err != nil {
+					return err
+				}
+				switch kind {
+				case 0, 1:
+					voter.Role = VoterRoleConstitutionalCommittee
+				case 2, 3:
+					voter.Role = VoterRoleDelegateRepresentative
+				case 4:
+					voter.Role = VoterRoleStakePoolOperator
+				default:
+					return fmt.Errorf("unknown voter type, %v", kind)
+				}
+				if kind < 4 {
+					voter.From = CredentialOriginVerificationKey
+					if kind%2 == 1 {
+						voter.From = CredentialOriginScript
+					}
+				}
+			case 1:
+				if voter.Role == VoterRoleStakePoolOperator {
+					voter.ID, err = decodePoolID(r)
+				} else {
+					voter.ID, err = decodeHex(r)
+				}
+			default:
+				err = fmt.Errorf("unexpected voter element, %v", i)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		return r.Map(func(int) error {
+			proposal, err := decodeGovActionID(r)
+			if err != nil {
+				return err
+			}
+
+			procedure := VotingProcedure{Voter: voter, Proposal: proposal}
+			err = r.Array(func(i int) (err error) {
+				switch i {
+				case 0:
+					var vote uint64
+					if vote, err = r.Uint(); err != nil {
+						return err
+					}
+					switch vote {
+					case 0:
+						procedure.Vote = VoteNo
+					case 1:
+						procedure.Vote = VoteYes
+					case 2:
+						procedure.Vote = VoteAbstain
+					default:
+						return fmt.Errorf("unknown vote, %v", vote)
+					}
+				case 1:
+					procedure.Anchor, err = decodeOptionalAnchor(r)
+				default:
+					err = fmt.Errorf("unexpected voting procedure element, %v", i)
+				}
+				return err
+			})
+			votes = append(votes, procedure)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode voting procedures: %w", err)
+	}
+	return votes, nil
+}
+
+func decodeProposalProcedure(r *cbor.Reader) (ProposalProcedure, error) {
+	var p ProposalProcedure
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			p.Deposit, err = decodeInt(r)
+		case 1:
+			p.RewardAccount, err = decodeRewardAccount(r)
+		case 2:
+			p.Action, err = decodeGovAction(r)
+		case 3:
+			p.Anchor, err = decodeAnchor(r)
+		default:
+			err = fmt.Errorf("unexpected proposal procedure element, %v", i)
+		}
+		return err
+	})
+	if err != nil {
+		return ProposalProcedure{}, fmt.Errorf("failed to decode proposal procedure: %w", err)
+	}
+	return p, nil
+}
+
+func decodeGovAction(r *cbor.Reader) (GovAction, error) {
+	var (
+		action GovAction
+		kind   uint64
+	)
+
+	err := r.Array(func(i int) (err error) {
+		if i == 0 {
+			if kind, err = r.Uint(); err != nil {
+				return err
+			}
+			switch kind {
+			case 0:
+				action.ParameterChange = &ParameterChangeAction{}
+			case 1:
+				action.HardForkInitiation = &HardForkInitiationAction{}
+			case 2:
+				action.TreasuryWithdrawals = &TreasuryWithdrawalsAction{}
+			case 3:
+				action.NoConfidence = &NoConfidenceAction{}
+			case 4:
+				action.UpdateCommittee = &UpdateCommitteeAction{}
+			case 5:
+				action.NewConstitution = &NewConstitutionAction{}
+			case 6:
+				action.Info = &InfoAction{}
+			default:
+				return fmt.Errorf("unknown governance action type, %v", kind)
+			}
+			return nil
+		}
+
+		switch a := action; {
+		case a.ParameterChange != nil && i == 1:
+			a.ParameterChange.PreviousAction, err = decodeOptionalGovActionID(r)
+		case a.ParameterChange != nil && i == 2:
+			a.ParameterChange.Parameters, err = decodeProtocolParametersUpdate(r)
+		case a.ParameterChange != nil && i == 3:
+			a.ParameterChange.GuardrailsScriptHash, err = decodeOptionalHex(r)
+
+		case a.HardForkInitiation != nil && i == 1:
+			a.HardForkInitiation.PreviousAction, err = decodeOptionalGovActionID(r)
+		case a.HardForkInitiation != nil && i == 2:
+			a.HardForkInitiation.ProtocolVersion, err = decodeProtocolVersion(r)
+
+		case a.TreasuryWithdrawals != nil && i == 1:
+			a.TreasuryWithdrawals.Withdrawals = map[string]num.Int{}
+			err = r.Map(func(int) error {
+				account, err := decodeRewardAccount(r)
+				if err != nil {
+					return err
+				}
+				v, err := decodeInt(r)
+				a.TreasuryWithdrawals.Withdrawals[account] = v
+				return err
+			})
+		case a.TreasuryWithdrawals != nil && i == 2:
+			a.TreasuryWithdrawals.GuardrailsScriptHash, err = decodeOptionalHex(r)
+
+		case a.NoConfidence != nil && i == 1:
+			a.NoConfidence.PreviousAction, err = decodeOptionalGovActionID(r)
+
+		case a.UpdateCommittee != nil && i == 1:
+			a.UpdateCommittee.PreviousAction, err = decodeOptionalGovActionID(r)
+		case a.UpdateCommittee != nil && i == 2:
+			err = r.Array(func(int) error {
+				credential, _, err := decodeCredential(r)
+				a.UpdateCommittee.Remove = append(a.UpdateCommittee.Remove, credential)
+				return err
+			})
+		case a.UpdateCommittee != nil && i == 3:
+			a.UpdateCommittee.Add = map[string]uint64{}
+			err = r.Map(func(int) error {
+				credential, _, err := decodeCredential(r)
+				if err != nil {
+					return err
+				}
+				epoch, err := r.Uint()
+				a.UpdateCommittee.Add[credential] = epoch
+				return err
+			})
+		case a.UpdateCommittee != nil && i == 4:
+			a.UpdateCommittee.Quorum, err = decodeRatio(r)
+
+		case a.NewConstitution != nil && i == 1:
+			a.NewConstitution.PreviousAction, err = decodeOptionalGovActionID(r)
+		case a.NewConstitution != nil && i == 2:
+			err = r.Array(func(j int) (err error) {
+				switch j {
+				case 0:
+					a.NewConstitution.Constitution.Anchor, err = decodeAnchor(r)
+				case 1:
+					a.NewConstitution.Constitution.GuardrailsScriptHash, err = decodeOptionalHex(r)
+				default:
+					err = fmt.Errorf("unexpected constitution element, %v", j)
+				}
+				return err
+			})
+
+		default:
+			err = fmt.Errorf("unexpected governance action element, %v, for type %v", i, kind)
+		}
+		return err
+	})
+	return action, err
+}
+
+func decodeProtocolVersion(r *cbor.Reader) (ProtocolVersion, error) {
+	var v ProtocolVersion
+	err := r.Array(func(i int) error {
+		n, err := r.Uint()
+		if err != nil {
+			return err
+		}
+		switch i {
+		case 0:
+			v.Major = uint32(n)
+		case 1:
+			v.Minor = uint32(n)
+		default:
+			return fmt.Errorf("unexpected protocol version element, %v", i)
+		}
+		return nil
+	})
+	return v, err
+}
+
+// decodeUpdate reads a pre-conway update proposal, [proposals, epoch]
+func decodeUpdate(r *cbor.Reader) (*Update, error) {
+	update := &Update{Proposal: map[string]ProtocolParameters{}}
+	err := r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			err = r.Map(func(int) error {
+				genesisKeyHash, err := decodeHex(r)
+				if err != nil {
+					return err
+				}
+				params, err := decodeProtocolParametersUpdate(r)
+				update.Proposal[genesisKeyHash] = params
+				return err
+			})
+		case 1:
+			update.Epoch, err = r.Uint()
+		default:
+			err = fmt.Errorf("unexpected update element, %v", i)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode update: %w", err)
+	}
+	return update, nil
+}
+
+func decodeProtocolParametersUpdate(r *cbor.Reader) (ProtocolParameters, error) {
+	var p ProtocolParameters
+
+	uintPtr := func(dst **uint64) error {
+		v, err := r.Uint()
+		*dst = &v
+		return err
+	}
+	intPtr := func(dst **num.Int) error {
+		v, err := decodeInt(r)
+		*dst = &v
+		return err
+	}
+	ratioPtr := func(dst **Ratio) error {
+		v, err := decodeRatio(r)
+		*dst = &v
+		return err
+	}
+	unitsPtr := func(dst **ExecutionUnits) error {
+		v, err := decodeExecutionUnits(r)
+		*dst = &v
+		return err
+	}
+	ratios := func(dst ...*Ratio) error {
+		return r.Array(func(i int) (err error) {
+			if i >= len(dst) {
+				return fmt.Errorf("unexpected threshold element, %v", i)
+			}
+			*dst[i], err = decodeRatio(r)
+			return err
+		})
+	}
+
+	err := r.Map(func(int) error {
+		key, err := r.Uint()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case 0:
+			err = uintPtr(&p.MinFeeCoefficient)
+		case 1:
+			err = uintPtr(&p.MinFeeConstant)
+		case 2:
+			err = uintPtr(&p.MaxBlockBodySize)
+		case 3:
+			err = uintPtr(&p.MaxTxSize)
+		case 4:
+			err = uintPtr(&p.MaxBlockHeaderSize)
+		case 5:
+			err = intPtr(&p.StakeKeyDeposit)
+		case 6:
+			err = intPtr(&p.PoolDeposit)
+		case 7:
+			err = uintPtr(&p.PoolRetirementEpochBound)
+		case 8:
+			err = uintPtr(&p.DesiredNumberOfPools)
+		case 9:
+			err = ratioPtr(&p.PoolInfluence)
+		case 10:
+			err = ratioPtr(&p.MonetaryExpansion)
+		case 11:
+			err = ratioPtr(&p.TreasuryExpansion)
+		case 12:
+			err = ratioPtr(&p.DecentralizationParameter)
+		case 13:
+			err = decodeExtraEntropy(r, &p)
+		case 14:
+			var v ProtocolVersion
+			if v, err = decodeProtocolVersion(r); err == nil {
+				p.ProtocolVersion = &v
+			}
+		case 15:
+			err = intPtr(&p.MinUtxoValue)
+		case 16:
+			err = intPtr(&p.MinPoolCost)
+		case 17:
+			err = intPtr(&p.CoinsPerUtxoByte)
+		case 18:
+			p.CostModels, err = decodeCostModels(r)
+		case 19:
+			var prices Prices
+			err = r.Array(func(i int) (err error) {
+				switch i {
+				case 0:
+					prices.Memory, err = decodeRatio(r)
+				case 1:
+					prices.Steps, err = decodeRatio(r)
+				default:
+					err = fmt.Errorf("unexpected prices element, %v", i)
+				}
+				return err
+			})
+			p.Prices = &prices
+		case 20:
+			err = unitsPtr(&p.MaxExecutionUnitsPerTransaction)
+		case 21:
+			err = unitsPtr(&p.MaxExecutionUnitsPerBlock)
+		case 22:
+			err = uintPtr(&p.MaxValueSize)
+		case 23:
+			err = uintPtr(&p.CollateralPercentage)
+		case 24:
+			err = uintPtr(&p.MaxCollateralInputs)
+		case 25:
+			t := &PoolVotingThresholds{}
+			p.PoolVotingThresholds = t
+			err = ratios(&t.MotionNoConfidence, &t.CommitteeNormal, &t.CommitteeNoConfidence, &t.HardForkInitiation, &t.PPSecurityGroup)
+		case 26:
+			t := &DRepVotingThresholds{}
+			p.DRepVotingThresholds = t
+			err = ratios(&t.MotionNoConfidence, &t.CommitteeNormal, &t.CommitteeNoConfidence, &t.UpdateConstitution, &t.HardForkInitiation,
+				&t.PPNetworkGroup, &t.PPEconomicGroup, &t.PPTechnicalGroup, &t.PPGovGroup, &t.TreasuryWithdrawal)
+		case 27:
+			err = uintPtr(&p.CommitteeMinSize)
+		case 28:
+			err = uintPtr(&p.CommitteeMaxTermLength)
+		case 29:
+			err = uintPtr(&p.GovernanceActionLifetime)
+		case 30:
+			err = intPtr(&p.GovernanceActionDeposit)
+		case 31:
+			err = intPtr(&p.DRepDeposit)
+		case 32:
+			err = uintPtr(&p.DRepActivity)
+		case 33:
+			err = ratioPtr(&p.MinFeeReferenceScripts)
+		default:
+			err = r.Skip()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode protocol parameter %v: %w", key, err)
+		}
+		return nil
+	})
+	return p, err
+}
+
+// decodeExtraEntropy reads the nonce, [0] for neutral or [1, hash]
+func decodeExtraEntropy(r *cbor.Reader, p *ProtocolParameters) error {
+	neutral := "neutral"
+	p.ExtraEntropy = &neutral
+	return r.Array(func(i int) (err error) {
+		switch i {
+		case 0:
+			_, err = r.Uint()
+		case 1:
+			var nonce string
+			if nonce, err = decodeHex(r); err == nil {
+				p.ExtraEntropy = &nonce
+			}
+		default:
+			err = fmt.Errorf("unexpected nonce element, %v", i)
+		}
+		return err
+	})
+}
+
+var costModelLanguages = []string{"plutus:v1", "plutus:v2", "plutus:v3"}
+
+// decodeCostModels reads cost models keyed by language; parameters are named
+// by their index as the cbor encoding carries no names
+func decodeCostModels(r *cbor.Reader) (CostModels, error) {
+	models := CostModels{}
+	err := r.Map(func(int) error {
+		language, err := r.Uint()
+		if err != nil {
+			return err
+		}
+		name := strconv.FormatUint(language, 10)
+		if language < uint64(len(costModelLanguages)) {
+			name = costModelLanguages[language]
+		}
+
+		model := CostModel{}
+		err = r.Array(func(i int) error {
+			v, err := decodeInt64(r)
+			model[strconv.Itoa(i)] = v
+			return err
+		})
+		models[name] = model
+		return err
+	})
+	return models, err
+}
@@ -0,0 +1,367 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/bech32"
+	internalcbor "github.com/SundaeSwap-finance/ogmigo/internal/cbor"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/address"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return data
+}
+
+func mustCBOR(t *testing.T, v interface{}) []byte {
+	data, err := cbor.Marshal(v)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return data
+}
+
+// cborMap encodes the key value pairs as a map, allowing keys that go maps
+// cannot hold such as byte strings and arrays
+func cborMap(t *testing.T, pairs ...interface{}) cbor.RawMessage {
+	data := []byte{byte(0xa0 + len(pairs)/2)}
+	for _, v := range pairs {
+		data = append(data, mustCBOR(t, v)...)
+	}
+	return data
+}
+
+func TestDecodeTx(t *testing.T) {
+	var (
+		txHash   = "1a3b2e3a49ab1c82b9a4f6f0f56a1b59b5e0f54c9d7bdfb4c13e2a5c7b8e9f01"
+		keyHash  = "9499315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+		poolHash = "2a748e3885f6f73320ad16a8331247b81fe01b8d39f57eec9caa5091"
+		vrfHash  = "c2b62ffa92ad18ffc117ea3abeb161a68885000a466f9c71db5e4731d6630061"
+		datum    = "d8799f4568656c6c6fff"
+		base     = address.MustParse(testBaseAddress)
+		stake, _ = base.StakeAddress()
+		stakeKey = mustHex(t, "337b62cfff6403a06a3acbc34f8c46003c69fe79a3628cefa9c47251")
+	)
+	poolID, err := bech32.Encode("pool", mustHex(t, poolHash))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	nativeScript := []interface{}{0, mustHex(t, keyHash)}
+	body := mustCBOR(t, map[uint64]interface{}{
+		0: []interface{}{[]interface{}{mustHex(t, txHash), 1}},
+		1: []interface{}{
+			[]interface{}{base.Bytes(), 2000000},
+			map[uint64]interface{}{
+				0: address.MustParse(testEnterpriseAddress).Bytes(),
+				1: []interface{}{1500000, cborMap(t, mustHex(t, testPolicyID), cborMap(t, []byte("abc"), 7))},
+				2: []interface{}{1, cbor.Tag{Number: 24, Content: mustHex(t, datum)}},
+				3: cbor.Tag{Number: 24, Content: mustCBOR(t, []interface{}{0, nativeScript})},
+			},
+		},
+		2: 180000,
+		3: 5000,
+		4: []interface{}{
+			[]interface{}{0, []interface{}{0, stakeKey}},
+			[]interface{}{2, []interface{}{0, stakeKey}, mustHex(t, poolHash)},
+			[]interface{}{3,
+				mustHex(t, poolHash), mustHex(t, vrfHash), 500, 340000000,
+				cbor.Tag{Number: 30, Content: []interface{}{1, 100}},
+				stake.Bytes(),
+				[]interface{}{stakeKey},
+				[]interface{}{
+					[]interface{}{0, 3001, []byte{10, 0, 0, 1}, nil},
+					[]interface{}{1, 3001, "relay.example.com"},
+				},
+				[]interface{}{"https://example.com/pool.json", mustHex(t, txHash)},
+			},
+		},
+		5:  cborMap(t, stake.Bytes(), 1000),
+		7:  mustHex(t, txHash),
+		8:  100,
+		9:  cborMap(t, mustHex(t, testPolicyID), cborMap(t, []byte("abc"), -3)),
+		15: 1,
+	})
+	witness := mustCBOR(t, map[uint64]interface{}{
+		0: []interface{}{[]interface{}{mustHex(t, vrfHash), mustHex(t, vrfHash+vrfHash)}},
+		1: []interface{}{nativeScript},
+	})
+	aux := mustCBOR(t, map[uint64]interface{}{
+		674: map[string]interface{}{"msg": []string{"hello"}},
+	})
+
+	var raw bytes.Buffer
+	raw.WriteByte(0x84)
+	raw.Write(body)
+	raw.Write(witness)
+	raw.WriteByte(internalcbor.True)
+	raw.Write(aux)
+
+	tx, err := DecodeTx(raw.Bytes())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	sum := blake2b.Sum256(body)
+	assert.Equal(t, hex.EncodeToString(sum[:]), tx.ID)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(raw.Bytes()), tx.Raw)
+	assert.Equal(t, "inputs", tx.InputSource)
+
+	assert.Equal(t, []TxIn{{TxHash: txHash, Index: 1}}, tx.Body.Inputs)
+	assert.Len(t, tx.Body.Outputs, 2)
+	assert.Equal(t, testBaseAddress, tx.Body.Outputs[0].Address)
+	assert.True(t, tx.Body.Outputs[0].Value.Coins.Cmp(num.Int64(2000000)) == 0)
+	assert.Equal(t, testEnterpriseAddress, tx.Body.Outputs[1].Address)
+	assert.Equal(t, datum, tx.Body.Outputs[1].Datum)
+	assert.True(t, tx.Body.Outputs[1].Value.Assets[AssetID(testPolicyID+".616263")].Cmp(num.Int64(7)) == 0)
+	assert.NotNil(t, tx.Body.Outputs[1].Script)
+	assert.Equal(t, keyHash, tx.Body.Outputs[1].Script.Native.KeyHash)
+	assert.True(t, tx.Body.Fee.Cmp(num.Int64(180000)) == 0)
+	assert.EqualValues(t, 5000, tx.Body.TimeToLive)
+	assert.Equal(t, ValidityInterval{InvalidBefore: 100, InvalidHereafter: 5000}, tx.Body.ValidityInterval)
+	assert.Equal(t, map[string]int64{stake.String(): 1000}, tx.Body.Withdrawals)
+	assert.True(t, tx.Body.Mint.Assets[AssetID(testPolicyID+".616263")].Cmp(num.Int64(-3)) == 0)
+	assert.Equal(t, json.RawMessage(`"mainnet"`), tx.Body.Network)
+
+	assert.Len(t, tx.Body.Certificates, 3)
+	assert.Equal(t, hex.EncodeToString(stakeKey), tx.Body.Certificates[0].StakeKeyRegistration)
+	assert.Equal(t, &StakeDelegation{Delegator: hex.EncodeToString(stakeKey), Delegatee: poolID}, tx.Body.Certificates[1].StakeDelegation)
+
+	pool := tx.Body.Certificates[2].PoolRegistration
+	if pool == nil {
+		t.Fatalf("got nil; want pool registration")
+	}
+	assert.Equal(t, poolID, pool.ID)
+	assert.Equal(t, vrfHash, pool.VRF)
+	assert.Equal(t, "1/100", pool.Margin.String())
+	assert.Equal(t, stake.String(), pool.RewardAccount)
+	assert.Equal(t, []string{hex.EncodeToString(stakeKey)}, pool.Owners)
+	assert.Len(t, pool.Relays, 2)
+	assert.Equal(t, "10.0.0.1", *pool.Relays[0].IPv4)
+	assert.EqualValues(t, 3001, *pool.Relays[0].Port)
+	assert.Equal(t, "relay.example.com", *pool.Relays[1].Hostname)
+	assert.Equal(t, &PoolMetadata{URL: "https://example.com/pool.json", Hash: txHash}, pool.Metadata)
+
+	signature := base64.StdEncoding.EncodeToString(mustHex(t, vrfHash+vrfHash))
+	assert.Equal(t, map[string]string{vrfHash: signature}, tx.Witness.Signatures)
+	assert.Len(t, tx.Witness.Scripts, 1)
+	for hash, script := range tx.Witness.Scripts {
+		assert.Len(t, hash, 56)
+		assert.Equal(t, keyHash, script.Native.KeyHash)
+	}
+
+	if tx.Metadata == nil {
+		t.Fatalf("got nil; want metadata")
+	}
+	assert.Equal(t, txHash, tx.Metadata.Hash)
+	msg, ok := tx.Metadata.Metadata().CIP20Message()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"hello"}, msg)
+
+	t.Run("raw body", func(t *testing.T) {
+		got, err := tx.RawBody()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, body, got)
+	})
+
+	t.Run("decode raw", func(t *testing.T) {
+		got, err := tx.DecodeRaw()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, tx.ID, got.ID)
+		assert.Equal(t, tx.Body.Inputs, got.Body.Inputs)
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		envelope := fmt.Sprintf(`{"type":"Tx BabbageEra","description":"","cborHex":"%x"}`, raw.Bytes())
+		got, err := DecodeTxEnvelope([]byte(envelope))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, tx.ID, got.ID)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodeTx(append(raw.Bytes(), 0x00))
+		assert.NotNil(t, err)
+
+		_, err = DecodeTx(raw.Bytes()[:raw.Len()-1])
+		assert.NotNil(t, err)
+
+		_, err = Tx{ID: "abc"}.RawBody()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDecodeTx_Governance(t *testing.T) {
+	var (
+		txHash  = "1a3b2e3a49ab1c82b9a4f6f0f56a1b59b5e0f54c9d7bdfb4c13e2a5c7b8e9f01"
+		keyHash = "9499315cd92eb5d8c4304e67b7e16ae36d61d34502694657811a2c8e"
+		base    = address.MustParse(testBaseAddress)
+		stake   = func() address.Address { a, _ := base.StakeAddress(); return a }()
+		anchor  = []interface{}{"https://example.com/anchor.json", mustHex(t, txHash)}
+	)
+
+	body := mustCBOR(t, map[uint64]interface{}{
+		0: []interface{}{[]interface{}{mustHex(t, txHash), 0}},
+		1: []interface{}{},
+		2: 200000,
+		4: []interface{}{
+			[]interface{}{9, []interface{}{0, mustHex(t, keyHash)}, []interface{}{2}},
+			[]interface{}{16, []interface{}{1, mustHex(t, keyHash)}, 500000000, anchor},
+		},
+		19: cborMap(t,
+			[]interface{}{2, mustHex(t, keyHash)},
+			cborMap(t, []interface{}{mustHex(t, txHash), 3}, []interface{}{1, nil}),
+		),
+		20: []interface{}{
+			[]interface{}{100000000000, stake.Bytes(), []interface{}{6}, anchor},
+			[]interface{}{100000000000, stake.Bytes(), []interface{}{
+				0, nil, map[uint64]interface{}{0: 44, 33: cbor.Tag{Number: 30, Content: []interface{}{15, 1}}}, nil,
+			}, anchor},
+		},
+		22: 1000000,
+	})
+
+	var raw bytes.Buffer
+	raw.WriteByte(0x84)
+	raw.Write(body)
+	raw.WriteByte(0xa0)
+	raw.WriteByte(internalcbor.True)
+	raw.WriteByte(internalcbor.Null)
+
+	tx, err := DecodeTx(raw.Bytes())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	assert.Len(t, tx.Body.Certificates, 2)
+	assert.Equal(t, &Delegation{Delegator: keyHash, DRep: &DRep{Type: DRepTypeAbstain}}, tx.Body.Certificates[0].VoteDelegation)
+	registration := tx.Body.Certificates[1].DRepRegistration
+	if registration == nil {
+		t.Fatalf("got nil; want drep registration")
+	}
+	assert.Equal(t, keyHash, registration.Credential)
+	assert.True(t, registration.Deposit.Cmp(num.Int64(500000000)) == 0)
+	assert.Equal(t, &Anchor{URL: "https://example.com/anchor.json", Hash: txHash}, registration.Anchor)
+
+	assert.Equal(t, []VotingProcedure{
+		{
+			Voter:    Voter{Role: VoterRoleDelegateRepresentative, From: CredentialOriginVerificationKey, ID: keyHash},
+			Proposal: GovActionID{TxHash: txHash, Index: 3},
+			Vote:     VoteYes,
+		},
+	}, tx.Body.Votes)
+
+	assert.Len(t, tx.Body.Proposals, 2)
+	assert.NotNil(t, tx.Body.Proposals[0].Action.Info)
+	assert.Equal(t, stake.String(), tx.Body.Proposals[0].RewardAccount)
+	change := tx.Body.Proposals[1].Action.ParameterChange
+	if change == nil {
+		t.Fatalf("got nil; want parameter change")
+	}
+	assert.Nil(t, change.PreviousAction)
+	assert.EqualValues(t, 44, *change.Parameters.MinFeeCoefficient)
+	assert.Equal(t, "15/1", change.Parameters.MinFeeReferenceScripts.String())
+	assert.True(t, tx.Body.Donation.Cmp(num.Int64(1000000)) == 0)
+	assert.Nil(t, tx.Metadata)
+}
+
+func TestDecodeTx_Mainnet(t *testing.T) {
+	// babbage era transaction from mainnet block 10558501
+	data, err := os.ReadFile("testdata/babbage_tx.hex")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	tx, err := DecodeTxHex(string(data))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	assert.Equal(t, "54db2319d7d9cc3f05e07bf4ac4d8688e83388fd64d9afd5417c1ee7b5fe34b4", tx.ID)
+	assert.Equal(t, "inputs", tx.InputSource)
+	assert.Equal(t, []TxIn{{TxHash: "3f5e6e7609563dc76da29430dca199c461af469c750c10098a0eb9be1ab4507a", Index: 0}}, tx.Body.Inputs)
+	assert.Equal(t, []TxIn{{TxHash: "c5f70d555f6bd7063f56c152b1323c0ef45bfa107de0ecec1d1085b22e06846b", Index: 0}}, tx.Body.Collaterals)
+	assert.Len(t, tx.Body.References, 2)
+	assert.True(t, tx.Body.Fee.Cmp(num.Int64(224990)) == 0)
+	if assert.NotNil(t, tx.Body.TotalCollateral) {
+		assert.EqualValues(t, 337485, *tx.Body.TotalCollateral)
+	}
+	assert.NotNil(t, tx.Body.CollateralReturn)
+	assert.Equal(t, []string{"771d5a28a99b9efa419d323f05605e7d89cdf22970617fe2069b3c24"}, tx.Body.RequiredExtraSignatures)
+	assert.Equal(t, ValidityInterval{InvalidBefore: 129124490, InvalidHereafter: 129126290}, tx.Body.ValidityInterval)
+
+	assert.Len(t, tx.Witness.Signatures, 2)
+	assert.Equal(t,
+		"RPMIJWrmcD18ZhPuJlkT0JTAkftUwhTREjyx3LbEdLDWRDcQZ5zDB+KClXsvap4IdNL6uQVe1xK9u0AtM/BVBQ==",
+		tx.Witness.Signatures["653f3e9b4fe440fdfbc3b77918fdfecd96f00d26ba90310f1de689f8a59a4e70"],
+	)
+	assert.Equal(t, Redeemers{
+		"spend:0": {Redeemer: "2H6A", ExecutionUnits: ExecutionUnits{Memory: 378119, Steps: 140452462}},
+	}, tx.Witness.Redeemers)
+
+	if tx.Metadata == nil {
+		t.Fatalf("got nil; want metadata")
+	}
+	assert.Equal(t, "c5a2e8fe5eac63b109e73529567b40a6ae0b94ae40fa44604ad03dd9ef255914", tx.Metadata.Hash)
+	msg, ok := tx.Metadata.Metadata().CIP20Message()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Axo: Closed by Protocol", "Reason: Trade Completed"}, msg)
+}
+
+func TestDecodeTx_Invalid(t *testing.T) {
+	for name, hexString := range map[string]string{
+		"missing is_valid": "84a3008001800200a0",
+		"invalid is_valid": "84a3008001800200a0f7f6",
+		"trailing bytes":   "84a3008001800200a0f5f600",
+	} {
+		if _, err := DecodeTx(mustHex(t, hexString)); err == nil {
+			t.Fatalf("got nil; want err for %v", name)
+		}
+	}
+}
+
+func TestDecodeRelay_IPv6(t *testing.T) {
+	// ::1 as serialized by the ledger, in little endian 32 bit words
+	ip := make([]byte, 16)
+	ip[12] = 1
+	data := mustCBOR(t, []interface{}{0, nil, nil, ip})
+
+	relay, err := decodeRelay(internalcbor.NewReader(data))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Nil(t, relay.Port)
+	assert.Equal(t, "::1", *relay.IPv6)
+}
@@ -0,0 +1,271 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/SundaeSwap-finance/ogmigo/internal/bech32"
+)
+
+type RedeemerPurpose string
+
+const (
+	RedeemerPurposeSpend       RedeemerPurpose = "spend"
+	RedeemerPurposeMint        RedeemerPurpose = "mint"
+	RedeemerPurposeCertificate RedeemerPurpose = "certificate"
+	RedeemerPurposeWithdrawal  RedeemerPurpose = "withdrawal"
+	RedeemerPurposeVote        RedeemerPurpose = "vote"
+	RedeemerPurposePropose     RedeemerPurpose = "propose"
+)
+
+// RedeemerPointer identifies the purpose and index of a redeemer e.g. spend:0
+type RedeemerPointer string
+
+func NewRedeemerPointer(purpose RedeemerPurpose, index int) RedeemerPointer {
+	return RedeemerPointer(string(purpose) + ":" + strconv.Itoa(index))
+}
+
+func (r RedeemerPointer) Purpose() RedeemerPurpose {
+	if index := strings.Index(string(r), ":"); index > 0 {
+		return RedeemerPurpose(r[:index])
+	}
+	return ""
+}
+
+func (r RedeemerPointer) Index() int {
+	if index := strings.Index(string(r), ":"); index > 0 {
+		if v, err := strconv.Atoi(string(r[index+1:])); err == nil {
+			return v
+		}
+	}
+	return -1
+}
+
+type ExecutionUnits struct {
+	Memory uint64 `json:"memory" dynamodbav:"memory"`
+	Steps  uint64 `json:"steps"  dynamodbav:"steps"`
+}
+
+type Redeemer struct {
+	Redeemer       string         `json:"redeemer"       dynamodbav:"redeemer"` // hex or base64 encoded plutus data
+	ExecutionUnits ExecutionUnits `json:"executionUnits" dynamodbav:"executionUnits"`
+}
+
+// Data returns the cbor encoded plutus data of the redeemer, decoded from hex
+// or, for ogmios < 5.5, base64
+func (r Redeemer) Data() ([]byte, error) {
+	data, err := decodeHexOrBase64(r.Redeemer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode redeemer, %v: %w", r.Redeemer, err)
+	}
+	return data, nil
+}
+
+// Redeemers maps redeemer pointer to redeemer
+type Redeemers map[RedeemerPointer]Redeemer
+
+func (r *Redeemers) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, redeemers were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, r); err != nil {
+			return fmt.Errorf("failed to unmarshal redeemers: %w", err)
+		}
+		return nil
+	}
+
+	var v map[RedeemerPointer]Redeemer
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal redeemers: %w", err)
+	}
+	*r = v
+	return nil
+}
+
+// RedeemerTarget identifies what a redeemer validates; the field matching
+// Purpose will be set
+type RedeemerTarget struct {
+	Purpose       RedeemerPurpose
+	Input         *TxIn              // spend
+	PolicyID      string             // mint
+	Certificate   *Certificate       // certificate
+	RewardAccount string             // withdrawal
+	Voter         *Voter             // vote
+	Proposal      *ProposalProcedure // propose
+}
+
+// RedeemerTarget returns the input, policy, certificate or reward account the
+// redeemer identified by pointer validates.  Pointers index into the ledger's
+// canonical ordering of each collection
+func (t TxBody) RedeemerTarget(pointer RedeemerPointer) (RedeemerTarget, error) {
+	index := pointer.Index()
+	if index < 0 {
+		return RedeemerTarget{}, fmt.Errorf("invalid redeemer pointer, %v", pointer)
+	}
+
+	target := RedeemerTarget{Purpose: pointer.Purpose()}
+	switch target.Purpose {
+	case RedeemerPurposeSpend:
+		inputs := t.SortedInputs()
+		if index >= len(inputs) {
+			return RedeemerTarget{}, fmt.Errorf("redeemer pointer out of range, %v", pointer)
+		}
+		target.Input = &inputs[index]
+
+	case RedeemerPurposeMint:
+		policies := t.MintPolicyIDs()
+		if index >= len(policies) {
+			return RedeemerTarget{}, fmt.Errorf("redeemer pointer out of range, %v", pointer)
+		}
+		target.PolicyID = policies[index]
+
+	case RedeemerPurposeCertificate:
+		if index >= len(t.Certificates) {
+			return RedeemerTarget{}, fmt.Errorf("redeemer pointer out of range, %v", pointer)
+		}
+		target.Certificate = &t.Certificates[index]
+
+	case RedeemerPurposeWithdrawal:
+		accounts, err := t.SortedWithdrawals()
+		if err != nil {
+			return RedeemerTarget{}, err
+		}
+		if index >= len(accounts) {
+			return RedeemerTarget{}, fmt.Errorf("redeemer pointer out of range, %v", pointer)
+		}
+		target.RewardAccount = accounts[index]
+
+	case RedeemerPurposeVote:
+		voters := t.SortedVoters()
+		if index >= len(voters) {
+			return RedeemerTarget{}, fmt.Errorf("redeemer pointer out of range, %v", pointer)
+		}
+		target.Voter = &voters[index]
+
+	case RedeemerPurposePropose:
+		if index >= len(t.Proposals) {
+			return RedeemerTarget{}, fmt.Errorf("redeemer pointer out of range, %v", pointer)
+		}
+		target.Proposal = &t.Proposals[index]
+
+	default:
+		return RedeemerTarget{}, fmt.Errorf("unknown redeemer purpose, %v", pointer)
+	}
+
+	return target, nil
+}
+
+// SortedInputs returns the inputs ordered by tx hash then index
+func (t TxBody) SortedInputs() []TxIn {
+	inputs := append([]TxIn(nil), t.Inputs...)
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].TxHash != inputs[j].TxHash {
+			return inputs[i].TxHash < inputs[j].TxHash
+		}
+		return inputs[i].Index < inputs[j].Index
+	})
+	return inputs
+}
+
+// MintPolicyIDs returns the distinct minted policy ids in sorted order
+func (t TxBody) MintPolicyIDs() []string {
+	if t.Mint == nil {
+		return nil
+	}
+
+	seen := map[string]struct{}{}
+	var policies []string
+	for assetID := range t.Mint.Assets {
+		policyID := assetID.PolicyID()
+		if _, ok := seen[policyID]; ok {
+			continue
+		}
+		seen[policyID] = struct{}{}
+		policies = append(policies, policyID)
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// SortedWithdrawals returns the withdrawal reward accounts in ledger order;
+// by network, then script credentials before key credentials, then hash
+func (t TxBody) SortedWithdrawals() ([]string, error) {
+	type account struct {
+		address string
+		network byte
+		script  bool
+		hash    []byte
+	}
+
+	var accounts []account
+	for address := range t.Withdrawals {
+		_, data, err := bech32.Decode(address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode reward account, %v: %w", address, err)
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("failed to decode reward account, %v: empty", address)
+		}
+		accounts = append(accounts, account{
+			address: address,
+			network: data[0] & 0x0f,
+			script:  data[0]&0x10 != 0,
+			hash:    data[1:],
+		})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		a, b := accounts[i], accounts[j]
+		switch {
+		case a.network != b.network:
+			return a.network < b.network
+		case a.script != b.script:
+			return a.script
+		default:
+			return bytes.Compare(a.hash, b.hash) < 0
+		}
+	})
+
+	var addresses []string
+	for _, a := range accounts {
+		addresses = append(addresses, a.address)
+	}
+	return addresses, nil
+}
+
+// RedeemerTargets returns the target validated by each redeemer in the witness
+func (t Tx) RedeemerTargets() (map[RedeemerPointer]RedeemerTarget, error) {
+	targets := map[RedeemerPointer]RedeemerTarget{}
+	for pointer := range t.Witness.Redeemers {
+		target, err := t.Body.RedeemerTarget(pointer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve redeemer target for tx, %v: %w", t.ID, err)
+		}
+		targets[pointer] = target
+	}
+	return targets, nil
+}
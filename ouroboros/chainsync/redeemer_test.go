@@ -0,0 +1,123 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+func TestRedeemerPointer(t *testing.T) {
+	pointer := NewRedeemerPointer(RedeemerPurposeMint, 3)
+	assert.Equal(t, RedeemerPointer("mint:3"), pointer)
+	assert.Equal(t, RedeemerPurposeMint, pointer.Purpose())
+	assert.Equal(t, 3, pointer.Index())
+
+	assert.Equal(t, RedeemerPurpose(""), RedeemerPointer("bogus").Purpose())
+	assert.Equal(t, -1, RedeemerPointer("spend:x").Index())
+}
+
+func TestRedeemers_JSON(t *testing.T) {
+	const data = `{"spend:0":{"redeemer":"2HmA","executionUnits":{"memory":4639986,"steps":1684643758}}}`
+
+	var redeemers Redeemers
+	if err := json.Unmarshal([]byte(data), &redeemers); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, Redeemers{
+		"spend:0": {Redeemer: "2HmA", ExecutionUnits: ExecutionUnits{Memory: 4639986, Steps: 1684643758}},
+	}, redeemers)
+
+	item, err := dynamodbattribute.Marshal(redeemers)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var got Redeemers
+	if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, redeemers, got)
+}
+
+func TestRedeemer_Data(t *testing.T) {
+	for _, encoded := range []string{"d87980", "2HmA"} {
+		data, err := Redeemer{Redeemer: encoded}.Data()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, "d87980", hex.EncodeToString(data))
+	}
+
+	_, err := Redeemer{Redeemer: "d8798!"}.Data()
+	assert.NotNil(t, err)
+}
+
+func TestTx_RedeemerTargets(t *testing.T) {
+	var (
+		keyAccount    = "stake1uyehkck0lajq8gr28t9uxnuvgcqrc6070x3k9r8048z8y5gh6ffgw"
+		scriptAccount = "stake178phkx6acpnf78fuvxn0mkew3l0fd058hzquvz7w36x4gtcccycj5"
+		certificate   = Certificate{StakeKeyDeregistration: "abc"}
+	)
+
+	tx := Tx{
+		ID: "tx",
+		Body: TxBody{
+			Certificates: []Certificate{certificate},
+			Inputs: []TxIn{
+				{TxHash: "bb", Index: 0},
+				{TxHash: "aa", Index: 1},
+				{TxHash: "aa", Index: 0},
+			},
+			Mint: &Value{Assets: map[AssetID]num.Int{
+				"ffff.01": num.Int64(1),
+				"0000.02": num.Int64(1),
+				"0000.03": num.Int64(1),
+			}},
+			Withdrawals: map[string]int64{keyAccount: 1, scriptAccount: 2},
+		},
+		Witness: Witness{
+			Redeemers: Redeemers{
+				"spend:1":       {},
+				"mint:1":        {},
+				"certificate:0": {},
+				"withdrawal:0":  {},
+				"withdrawal:1":  {},
+			},
+		},
+	}
+
+	targets, err := tx.RedeemerTargets()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, map[RedeemerPointer]RedeemerTarget{
+		"spend:1":       {Purpose: RedeemerPurposeSpend, Input: &TxIn{TxHash: "aa", Index: 1}},
+		"mint:1":        {Purpose: RedeemerPurposeMint, PolicyID: "ffff"},
+		"certificate:0": {Purpose: RedeemerPurposeCertificate, Certificate: &certificate},
+		"withdrawal:0":  {Purpose: RedeemerPurposeWithdrawal, RewardAccount: scriptAccount},
+		"withdrawal:1":  {Purpose: RedeemerPurposeWithdrawal, RewardAccount: keyAccount},
+	}, targets)
+
+	tx.Witness.Redeemers = Redeemers{"spend:3": {}}
+	if _, err := tx.RedeemerTargets(); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
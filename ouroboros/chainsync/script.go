@@ -0,0 +1,208 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// Script is a tagged union of the script languages; exactly one field will be set
+type Script struct {
+	Native   *NativeScript `json:"native,omitempty"    dynamodbav:"native,omitempty"`
+	PlutusV1 string        `json:"plutus:v1,omitempty" dynamodbav:"plutus:v1,omitempty"` // hex or base64 encoded cbor
+	PlutusV2 string        `json:"plutus:v2,omitempty" dynamodbav:"plutus:v2,omitempty"` // hex or base64 encoded cbor
+	PlutusV3 string        `json:"plutus:v3,omitempty" dynamodbav:"plutus:v3,omitempty"` // hex or base64 encoded cbor
+}
+
+// Plutus returns the plutus language version, 1 to 3, and the decoded script
+// bytes.  version is 0 if s is not a plutus script.  As with Datums, the script
+// is decoded as hex, which ogmios >= 5.5 emits, falling back to base64
+func (s Script) Plutus() (version int, code []byte, err error) {
+	var encoded string
+	switch {
+	case s.PlutusV1 != "":
+		version, encoded = 1, s.PlutusV1
+	case s.PlutusV2 != "":
+		version, encoded = 2, s.PlutusV2
+	case s.PlutusV3 != "":
+		version, encoded = 3, s.PlutusV3
+	default:
+		return 0, nil, nil
+	}
+
+	code, err = decodeHexOrBase64(encoded)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to decode plutus:v%v script: %w", version, err)
+	}
+	return version, code, nil
+}
+
+// decodeHexOrBase64 decodes s as hex, falling back to base64 for values
+// written by ogmios < 5.5
+func decodeHexOrBase64(s string) ([]byte, error) {
+	if data, err := hex.DecodeString(s); err == nil {
+		return data, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// script allows the default dynamodb decoding to be used
+type script Script
+
+func (s *Script) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, scripts were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, s); err != nil {
+			return fmt.Errorf("failed to unmarshal script: %w", err)
+		}
+		return nil
+	}
+
+	var v script
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal script: %w", err)
+	}
+	*s = Script(v)
+	return nil
+}
+
+// Scripts maps script hash to script
+type Scripts map[string]Script
+
+func (s *Scripts) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil {
+		return nil
+	}
+
+	// for backwards compatibility, scripts were previously stored as raw json
+	if len(item.B) > 0 {
+		if err := json.Unmarshal(item.B, s); err != nil {
+			return fmt.Errorf("failed to unmarshal scripts: %w", err)
+		}
+		return nil
+	}
+
+	var v map[string]Script
+	if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+		return fmt.Errorf("failed to unmarshal scripts: %w", err)
+	}
+	*s = v
+	return nil
+}
+
+type NativeScriptType string
+
+const (
+	NativeScriptTypeSignature NativeScriptType = "sig"
+	NativeScriptTypeAll       NativeScriptType = "all"
+	NativeScriptTypeAny       NativeScriptType = "any"
+	NativeScriptTypeNOf       NativeScriptType = "nOf"
+	NativeScriptTypeStartsAt  NativeScriptType = "startsAt"
+	NativeScriptTypeExpiresAt NativeScriptType = "expiresAt"
+)
+
+// NativeScript represents a timelock script.  Ogmios encodes signatures as the
+// bare key hash, n-of-m scripts as an object keyed by n, and all other kinds
+// as an object keyed by type
+type NativeScript struct {
+	Type    NativeScriptType `dynamodbav:"type"`
+	KeyHash string           `dynamodbav:"keyHash,omitempty"` // sig
+	Scripts []NativeScript   `dynamodbav:"scripts,omitempty"` // all, any, nOf
+	N       int              `dynamodbav:"n,omitempty"`       // nOf
+	Slot    uint64           `dynamodbav:"slot,omitempty"`    // startsAt, expiresAt
+}
+
+func (n NativeScript) MarshalJSON() ([]byte, error) {
+	switch n.Type {
+	case NativeScriptTypeSignature:
+		return json.Marshal(n.KeyHash)
+	case NativeScriptTypeAll, NativeScriptTypeAny:
+		return json.Marshal(map[string][]NativeScript{string(n.Type): nonNil(n.Scripts)})
+	case NativeScriptTypeNOf:
+		return json.Marshal(map[string][]NativeScript{strconv.Itoa(n.N): nonNil(n.Scripts)})
+	case NativeScriptTypeStartsAt, NativeScriptTypeExpiresAt:
+		return json.Marshal(map[string]uint64{string(n.Type): n.Slot})
+	default:
+		return nil, fmt.Errorf("unable to marshal native script: unknown type, %v", n.Type)
+	}
+}
+
+func nonNil(scripts []NativeScript) []NativeScript {
+	if scripts == nil {
+		return []NativeScript{}
+	}
+	return scripts
+}
+
+func (n *NativeScript) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var keyHash string
+		if err := json.Unmarshal(data, &keyHash); err != nil {
+			return fmt.Errorf("failed to unmarshal native script: %w", err)
+		}
+		*n = NativeScript{Type: NativeScriptTypeSignature, KeyHash: keyHash}
+		return nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal native script: %w", err)
+	}
+	if len(raw) != 1 {
+		return fmt.Errorf("failed to unmarshal native script: expected exactly one key, %v", string(data))
+	}
+
+	for key, value := range raw {
+		switch t := NativeScriptType(key); t {
+		case NativeScriptTypeAll, NativeScriptTypeAny:
+			var scripts []NativeScript
+			if err := json.Unmarshal(value, &scripts); err != nil {
+				return fmt.Errorf("failed to unmarshal native script, %v: %w", key, err)
+			}
+			*n = NativeScript{Type: t, Scripts: scripts}
+
+		case NativeScriptTypeStartsAt, NativeScriptTypeExpiresAt:
+			var slot uint64
+			if err := json.Unmarshal(value, &slot); err != nil {
+				return fmt.Errorf("failed to unmarshal native script, %v: %w", key, err)
+			}
+			*n = NativeScript{Type: t, Slot: slot}
+
+		default:
+			required, err := strconv.Atoi(key)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal native script: unknown type, %v", key)
+			}
+			var scripts []NativeScript
+			if err := json.Unmarshal(value, &scripts); err != nil {
+				return fmt.Errorf("failed to unmarshal native script, %v: %w", key, err)
+			}
+			*n = NativeScript{Type: NativeScriptTypeNOf, N: required, Scripts: scripts}
+		}
+	}
+
+	return nil
+}
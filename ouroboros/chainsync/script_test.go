@@ -0,0 +1,106 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+const scriptsJSON = `{
+  "b5ae663aaea8e500157bdf4baafd6f5ba0ce5759f7cd4101fc132f54": {"native": {"any": [
+    "3c07030e36bfff7cf7ffd3b7e59ef6bd7f0f16e4a7cc0c5fec9d5aaf",
+    {"all": [{"startsAt": 1000}, {"expiresAt": 2000}]},
+    {"2": ["aa", "bb", "cc"]}
+  ]}},
+  "4020e7fc2de75a0729c3cc3af715b34d98381e0cdbcfa99c950bc3ac": {"plutus:v1": "Tk0BAAAzIiIgBRIAEgAR"},
+  "ba158766c1bae60e2117ee8987621441fac66a5e0fb9c7aca58cf20a": {"plutus:v2": "Tk0BAAAzIiIgBRIAEgAR"}
+}`
+
+func TestScripts_JSON(t *testing.T) {
+	var scripts Scripts
+	err := json.Unmarshal([]byte(scriptsJSON), &scripts)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	native := scripts["b5ae663aaea8e500157bdf4baafd6f5ba0ce5759f7cd4101fc132f54"].Native
+	assert.Equal(t, NativeScriptTypeAny, native.Type)
+	assert.Equal(t, NativeScript{Type: NativeScriptTypeSignature, KeyHash: "3c07030e36bfff7cf7ffd3b7e59ef6bd7f0f16e4a7cc0c5fec9d5aaf"}, native.Scripts[0])
+	assert.Equal(t, NativeScriptTypeAll, native.Scripts[1].Type)
+	assert.Equal(t, NativeScript{Type: NativeScriptTypeStartsAt, Slot: 1000}, native.Scripts[1].Scripts[0])
+	assert.Equal(t, NativeScript{Type: NativeScriptTypeExpiresAt, Slot: 2000}, native.Scripts[1].Scripts[1])
+	assert.Equal(t, NativeScriptTypeNOf, native.Scripts[2].Type)
+	assert.Equal(t, 2, native.Scripts[2].N)
+	assert.Len(t, native.Scripts[2].Scripts, 3)
+
+	assert.Equal(t, "Tk0BAAAzIiIgBRIAEgAR", scripts["4020e7fc2de75a0729c3cc3af715b34d98381e0cdbcfa99c950bc3ac"].PlutusV1)
+	assert.Equal(t, "Tk0BAAAzIiIgBRIAEgAR", scripts["ba158766c1bae60e2117ee8987621441fac66a5e0fb9c7aca58cf20a"].PlutusV2)
+
+	data, err := json.Marshal(scripts)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.JSONEq(t, scriptsJSON, string(data))
+
+	item, err := dynamodbattribute.Marshal(scripts)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var got Scripts
+	if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, scripts, got)
+}
+
+func TestScript_Plutus(t *testing.T) {
+	version, code, err := Script{PlutusV2: "Tk0BAAAzIiIgBRIAEgAR"}.Plutus()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, 2, version)
+	assert.Equal(t, "4e4d01000033222220051200120011", hex.EncodeToString(code))
+
+	version, code, err = Script{PlutusV1: "4e4d01000033222220051200120011"}.Plutus()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, 1, version)
+	assert.Equal(t, "4e4d01000033222220051200120011", hex.EncodeToString(code))
+
+	version, _, err = Script{Native: &NativeScript{Type: NativeScriptTypeSignature}}.Plutus()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, 0, version)
+
+	if _, _, err := (Script{PlutusV1: "4e4d01000033222220051200120011!"}).Plutus(); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestNativeScript_UnmarshalJSON_Invalid(t *testing.T) {
+	for _, s := range []string{`{}`, `{"bogus": []}`, `{"any": [], "all": []}`, `{"startsAt": "x"}`} {
+		var script NativeScript
+		if err := json.Unmarshal([]byte(s), &script); err == nil {
+			t.Fatalf("got nil; want err for %v", s)
+		}
+	}
+}
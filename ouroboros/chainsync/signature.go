@@ -0,0 +1,211 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrInvalidSignature is returned, wrapped, when a witness signature does not
+// verify against the transaction id
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrUnverifiedID is returned, wrapped, when a tx id can't be checked against
+// the raw transaction
+var ErrUnverifiedID = errors.New("unverified tx id")
+
+// BootstrapWitness is a byron era key witness
+type BootstrapWitness struct {
+	Key               string `json:"key"`               // hex encoded ed25519 public key
+	Signature         string `json:"signature"`         // base64 encoded signature
+	ChainCode         string `json:"chainCode"`         // hex encoded bip32 chain code
+	AddressAttributes string `json:"addressAttributes"` // base64 encoded cbor address attributes
+}
+
+// BootstrapWitnesses decodes Bootstrap
+func (w Witness) BootstrapWitnesses() ([]BootstrapWitness, error) {
+	var witnesses []BootstrapWitness
+	for _, raw := range w.Bootstrap {
+		var bw BootstrapWitness
+		if err := json.Unmarshal(raw, &bw); err != nil {
+			return nil, fmt.Errorf("failed to decode bootstrap witness: %w", err)
+		}
+		witnesses = append(witnesses, bw)
+	}
+	return witnesses, nil
+}
+
+// KeyHash returns the hex encoded address root of the byron address the
+// witness spends from, which stands in for a key hash
+func (b BootstrapWitness) KeyHash() (string, error) {
+	key, err := hex.DecodeString(b.Key + b.ChainCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode bootstrap key: %w", err)
+	}
+	if len(key) != 64 {
+		return "", fmt.Errorf("failed to decode bootstrap key: got %v bytes; want 64", len(key))
+	}
+	attributes, err := base64.StdEncoding.DecodeString(b.AddressAttributes)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode bootstrap address attributes: %w", err)
+	}
+
+	// [0, [0, key || chain code], attributes]
+	root := append([]byte{0x83, 0x00, 0x82, 0x00, 0x58, 0x40}, key...)
+	root = append(root, attributes...)
+	sum := sha3.Sum256(root)
+	h, _ := blake2b.New(28, nil)
+	h.Write(sum[:])
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SignatureOption provides functional options for signature verification
+type SignatureOption func(opts *signatureOptions)
+
+type signatureOptions struct {
+	trustID bool // accept ID as given when there is no Raw to verify it against
+}
+
+// WithTrustedID verifies signatures against ID as given when the tx has no Raw
+// body e.g. the tx came from a trusted ogmios node.  ID is always checked
+// against Raw when Raw is set
+func WithTrustedID() SignatureOption {
+	return func(opts *signatureOptions) {
+		opts.trustID = true
+	}
+}
+
+// VerifySignatures checks every vkey and bootstrap signature against the tx
+// id; see Signers
+func (t Tx) VerifySignatures(opts ...SignatureOption) error {
+	_, err := t.Signers(opts...)
+	return err
+}
+
+// Signers returns the key hashes of the verified witnesses, sorted.  An error
+// wrapping ErrInvalidSignature is returned if any signature fails to verify.
+//
+// The signatures are checked against ID, which must first match the hash of
+// the body in Raw.  Without Raw, an error wrapping ErrUnverifiedID is returned
+// unless WithTrustedID is passed.  Body is assumed to have been decoded from
+// Raw, as DecodeTx does
+func (t Tx) Signers(opts ...SignatureOption) ([]string, error) {
+	var options signatureOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	switch {
+	case t.Raw != "":
+		if err := t.VerifyID(); err != nil {
+			return nil, err
+		}
+	case !options.trustID:
+		return nil, fmt.Errorf("failed to verify signatures for tx, %v: %w", t.ID, ErrUnverifiedID)
+	}
+
+	message, err := hex.DecodeString(t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tx id, %v: %w", t.ID, err)
+	}
+
+	var signers []string
+	for key, signature := range t.Witness.Signatures {
+		if err := verifySignature(key, signature, message); err != nil {
+			return nil, err
+		}
+		keyHash, err := HashKey(key)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, keyHash)
+	}
+
+	bootstrap, err := t.Witness.BootstrapWitnesses()
+	if err != nil {
+		return nil, err
+	}
+	for _, bw := range bootstrap {
+		if err := verifySignature(bw.Key, bw.Signature, message); err != nil {
+			return nil, err
+		}
+		keyHash, err := bw.KeyHash()
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, keyHash)
+	}
+
+	sort.Strings(signers)
+	return signers, nil
+}
+
+// RequiredSignatures reports which of the RequiredExtraSignatures key hashes
+// are satisfied by verified witnesses and which are missing
+func (t Tx) RequiredSignatures(opts ...SignatureOption) (satisfied, missing []string, err error) {
+	signers, err := t.Signers(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signed := map[string]struct{}{}
+	for _, s := range signers {
+		signed[s] = struct{}{}
+	}
+	for _, keyHash := range t.Body.RequiredExtraSignatures {
+		if _, ok := signed[keyHash]; ok {
+			satisfied = append(satisfied, keyHash)
+		} else {
+			missing = append(missing, keyHash)
+		}
+	}
+	return satisfied, missing, nil
+}
+
+// HashKey returns the hex encoded blake2b-224 hash of a hex encoded
+// verification key
+func HashKey(key string) (string, error) {
+	data, err := hex.DecodeString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode verification key, %v: %w", key, err)
+	}
+	h, _ := blake2b.New(28, nil)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func verifySignature(key, signature string, message []byte) error {
+	publicKey, err := hex.DecodeString(key)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("failed to decode verification key, %v", key)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature for key, %v: %w", key, err)
+	}
+	if !ed25519.Verify(publicKey, message, sig) {
+		return fmt.Errorf("signature for key, %v: %w", key, ErrInvalidSignature)
+	}
+	return nil
+}
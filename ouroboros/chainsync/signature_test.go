@@ -0,0 +1,202 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_Signers(t *testing.T) {
+	var (
+		id      = HashTxBody(mustHex(t, "a3008001800200"))
+		message = mustHex(t, id)
+		alice   = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+		bob     = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+		carol   = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{3}, ed25519.SeedSize))
+	)
+	vkey := func(k ed25519.PrivateKey) string { return hex.EncodeToString(k.Public().(ed25519.PublicKey)) }
+	sign := func(k ed25519.PrivateKey) string { return base64.StdEncoding.EncodeToString(ed25519.Sign(k, message)) }
+	keyHash := func(k ed25519.PrivateKey) string {
+		v, err := HashKey(vkey(k))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		return v
+	}
+
+	bootstrap, err := json.Marshal(BootstrapWitness{
+		Key:               vkey(carol),
+		Signature:         sign(carol),
+		ChainCode:         hex.EncodeToString(bytes.Repeat([]byte{4}, 32)),
+		AddressAttributes: "oA==",
+	})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	tx := Tx{
+		ID:  id,
+		Raw: base64.StdEncoding.EncodeToString(mustHex(t, "84a3008001800200a0f5f6")),
+		Body: TxBody{
+			RequiredExtraSignatures: []string{keyHash(alice), keyHash(bob)},
+		},
+		Witness: Witness{
+			Signatures: map[string]string{vkey(alice): sign(alice)},
+			Bootstrap:  []json.RawMessage{bootstrap},
+		},
+	}
+
+	signers, err := tx.Signers()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Len(t, signers, 2)
+	assert.Contains(t, signers, keyHash(alice))
+
+	satisfied, missing, err := tx.RequiredSignatures()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, []string{keyHash(alice)}, satisfied)
+	assert.Equal(t, []string{keyHash(bob)}, missing)
+
+	t.Run("invalid", func(t *testing.T) {
+		tx := tx
+		tx.Witness.Signatures = map[string]string{vkey(bob): sign(alice)}
+		if err := tx.VerifySignatures(); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("got %v; want %v", err, ErrInvalidSignature)
+		}
+	})
+
+	t.Run("tampered raw", func(t *testing.T) {
+		tx := tx
+		tx.Raw = base64.StdEncoding.EncodeToString(mustHex(t, "84a3008001800201a0f5f6"))
+		if err := tx.VerifySignatures(); !errors.Is(err, ErrHashMismatch) {
+			t.Fatalf("got %v; want %v", err, ErrHashMismatch)
+		}
+	})
+
+	t.Run("trusted id", func(t *testing.T) {
+		tx := tx
+		tx.Raw = ""
+		if err := tx.VerifySignatures(); !errors.Is(err, ErrUnverifiedID) {
+			t.Fatalf("got %v; want %v", err, ErrUnverifiedID)
+		}
+		if err := tx.VerifySignatures(WithTrustedID()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+
+	t.Run("bootstrap", func(t *testing.T) {
+		witnesses, err := tx.Witness.BootstrapWitnesses()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Len(t, witnesses, 1)
+
+		got, err := witnesses[0].KeyHash()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Len(t, got, 56)
+		assert.Contains(t, signers, got)
+
+		witnesses[0].ChainCode = ""
+		_, err = witnesses[0].KeyHash()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestTx_VerifySignatures(t *testing.T) {
+	t.Run("dynamodb", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/scoop.json")
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var item map[string]*dynamodb.AttributeValue
+		if err := json.Unmarshal(data, &item); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var tx Tx
+		if err := dynamodbattribute.Unmarshal(item["tx"], &tx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, "AGIbXK7AdVClxePUhHAgaMOZ0mZRlACuOh7N6DbOQ/AL9SFxP5kzffsiGBH6eZt3jB3obe+OzsQmtVzgOD88Bw==",
+			tx.Witness.Signatures["88bc0a6abb1ffafebd8e5bb8efe11675149f8bd421d7e9ed2cb0af8cd3881f1a"])
+		assert.Nil(t, tx.VerifySignatures(WithTrustedID()))
+	})
+
+	t.Run("bootstrap", func(t *testing.T) {
+		// mainnet transaction 5389bf21d34f96826e378606430d7f1dbd6dc20bf907c067df853c2b2a838211
+		const data = `{
+  "id": "5389bf21d34f96826e378606430d7f1dbd6dc20bf907c067df853c2b2a838211",
+  "witness": {
+    "bootstrap": [
+      {
+        "key": "475d8196c83669c1e1077b2cbeb715382bb0f4934d1ed8257479cb79590531d2",
+        "signature": "KxvCQCA4RiyGm0pFicLJmAeW9J5XWlWIuHukZA/GVL/RnTB6+Fe9KX2mtS22SDG+jvPXmGwe9EYyd8Wbe3ITBw==",
+        "chainCode": "1aa9a1457792bb85ace5e76ee2db449aa59960c34b100f3e3608636ed715fc1f",
+        "addressAttributes": "oQFYHlgcg9Pi3zDt+XWpUlaFo49kNB6biuL1JddsEwNmvQ=="
+      }
+    ],
+    "signatures": {
+      "b5a8de928eaeb70257fb5a2a0b4876a6b7409627c77dd0a4acdffb7f1198c8f2": "lvKfbSd8sfC6qSp4oBZwvrk8Nkzk1LhiRSWtw45X9hWfwfPD8UfQ920t98aCpNKPhwuWQRaqalR4/Q2ZfGfQBA=="
+    }
+  }
+}`
+
+		var tx Tx
+		if err := json.Unmarshal([]byte(data), &tx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		signers, err := tx.Signers(WithTrustedID())
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Len(t, signers, 2)
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/babbage_tx.hex")
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		tx, err := DecodeTxHex(string(data))
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		satisfied, missing, err := tx.RequiredSignatures()
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		assert.Equal(t, []string{"771d5a28a99b9efa419d323f05605e7d89cdf22970617fe2069b3c24"}, satisfied)
+		assert.Empty(t, missing)
+	})
+}
@@ -34,7 +34,7 @@ import (
 )
 
 var (
-	bNil       = []byte("nil")
+	bNil = []byte("nil")
 )
 
 type AssetID string
@@ -149,6 +149,8 @@ func (p PointStruct) Point() Point {
 	}
 }
 
+// Point is either origin or a block.  Equal points may differ under ==, so use
+// Equal to compare them and Key as a Go map key
 type Point struct {
 	pointType   PointType
 	pointString PointString
@@ -321,8 +323,8 @@ func (p *Point) UnmarshalJSON(data []byte) error {
 }
 
 type ProtocolVersion struct {
-	Major uint32
-	Minor uint32
+	Major uint32 `json:"major"`
+	Minor uint32 `json:"minor"`
 	Patch uint32 `json:"patch,omitempty"`
 }
 
@@ -341,32 +343,14 @@ type RollForwardBlock struct {
 	Alonzo  *Block      `json:"alonzo,omitempty"  dynamodbav:"alonzo,omitempty"`
 	Babbage *Block      `json:"babbage,omitempty" dynamodbav:"babbage,omitempty"`
 	Byron   *ByronBlock `json:"byron,omitempty"   dynamodbav:"byron,omitempty"`
+	Conway  *Block      `json:"conway,omitempty"  dynamodbav:"conway,omitempty"`
 	Mary    *Block      `json:"mary,omitempty"    dynamodbav:"mary,omitempty"`
 	Shelley *Block      `json:"shelley,omitempty" dynamodbav:"shelley,omitempty"`
 }
 
 func (r RollForwardBlock) PointStruct() PointStruct {
-	if byron := r.Byron; byron != nil {
-		return PointStruct{
-			BlockNo: byron.Header.BlockHeight,
-			Hash:    byron.Hash,
-			Slot:    byron.Header.Slot,
-		}
-	}
-
-	var block *Block
-	switch {
-	case r.Allegra != nil:
-		block = r.Allegra
-	case r.Alonzo != nil:
-		block = r.Alonzo
-	case r.Mary != nil:
-		block = r.Mary
-	case r.Shelley != nil:
-		block = r.Shelley
-	case r.Babbage != nil:
-		block = r.Babbage
-	default:
+	block := r.Block()
+	if block == nil {
 		return PointStruct{}
 	}
 
@@ -394,32 +378,36 @@ type Response struct {
 }
 
 type Tx struct {
-	ID          string          `json:"id,omitempty"       dynamodbav:"id,omitempty"`
-	InputSource string          `json:"inputSource,omitempty"  dynamodbav:"inputSource,omitempty"`
-	Body        TxBody          `json:"body,omitempty"     dynamodbav:"body,omitempty"`
-	Witness     Witness         `json:"witness,omitempty"  dynamodbav:"witness,omitempty"`
-	Metadata    json.RawMessage `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
+	ID          string         `json:"id,omitempty"       dynamodbav:"id,omitempty"`
+	InputSource string         `json:"inputSource,omitempty"  dynamodbav:"inputSource,omitempty"`
+	Body        TxBody         `json:"body,omitempty"     dynamodbav:"body,omitempty"`
+	Witness     Witness        `json:"witness,omitempty"  dynamodbav:"witness,omitempty"`
+	Metadata    *AuxiliaryData `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
 	// Raw serialized transaction, base64.
 	Raw string `json:"raw,omitempty" dynamodbav:"raw,omitempty"`
 }
 
 type TxBody struct {
-	Certificates            []json.RawMessage `json:"certificates,omitempty"            dynamodbav:"certificates,omitempty"`
-	Collaterals             []TxIn            `json:"collaterals,omitempty"             dynamodbav:"collaterals,omitempty"`
-	Fee                     num.Int           `json:"fee,omitempty"                     dynamodbav:"fee,omitempty"`
-	Inputs                  []TxIn            `json:"inputs,omitempty"                  dynamodbav:"inputs,omitempty"`
-	Mint                    *Value            `json:"mint,omitempty"                    dynamodbav:"mint,omitempty"`
-	Network                 json.RawMessage   `json:"network,omitempty"                 dynamodbav:"network,omitempty"`
-	Outputs                 TxOuts            `json:"outputs,omitempty"                 dynamodbav:"outputs,omitempty"`
-	RequiredExtraSignatures []string          `json:"requiredExtraSignatures,omitempty" dynamodbav:"requiredExtraSignatures,omitempty"`
-	ScriptIntegrityHash     string            `json:"scriptIntegrityHash,omitempty"     dynamodbav:"scriptIntegrityHash,omitempty"`
-	TimeToLive              int64             `json:"timeToLive,omitempty"              dynamodbav:"timeToLive,omitempty"`
-	Update                  json.RawMessage   `json:"update,omitempty"                  dynamodbav:"update,omitempty"`
-	ValidityInterval        ValidityInterval  `json:"validityInterval"                  dynamodbav:"validityInterval,omitempty"`
-	Withdrawals             map[string]int64  `json:"withdrawals,omitempty"             dynamodbav:"withdrawals,omitempty"`
-	CollateralReturn        *TxOut            `json:"collateralReturn,omitempty"        dynamodbav:"collateralReturn,omitempty"`
-	TotalCollateral         *int64            `json:"totalCollateral,omitempty"         dynamodbav:"totalCollateral,omitempty"`
-	References              []TxIn            `json:"references,omitempty"              dynamodbav:"references,omitempty"`
+	Certificates            []Certificate       `json:"certificates,omitempty"            dynamodbav:"certificates,omitempty"`
+	Collaterals             []TxIn              `json:"collaterals,omitempty"             dynamodbav:"collaterals,omitempty"`
+	Fee                     num.Int             `json:"fee,omitempty"                     dynamodbav:"fee,omitempty"`
+	Inputs                  []TxIn              `json:"inputs,omitempty"                  dynamodbav:"inputs,omitempty"`
+	Mint                    *Value              `json:"mint,omitempty"                    dynamodbav:"mint,omitempty"`
+	Network                 json.RawMessage     `json:"network,omitempty"                 dynamodbav:"network,omitempty"`
+	Outputs                 TxOuts              `json:"outputs,omitempty"                 dynamodbav:"outputs,omitempty"`
+	RequiredExtraSignatures []string            `json:"requiredExtraSignatures,omitempty" dynamodbav:"requiredExtraSignatures,omitempty"`
+	ScriptIntegrityHash     string              `json:"scriptIntegrityHash,omitempty"     dynamodbav:"scriptIntegrityHash,omitempty"`
+	TimeToLive              int64               `json:"timeToLive,omitempty"              dynamodbav:"timeToLive,omitempty"`
+	Update                  *Update             `json:"update,omitempty"                  dynamodbav:"update,omitempty"`
+	ValidityInterval        ValidityInterval    `json:"validityInterval"                  dynamodbav:"validityInterval,omitempty"`
+	Withdrawals             map[string]int64    `json:"withdrawals,omitempty"             dynamodbav:"withdrawals,omitempty"`
+	CollateralReturn        *TxOut              `json:"collateralReturn,omitempty"        dynamodbav:"collateralReturn,omitempty"`
+	TotalCollateral         *int64              `json:"totalCollateral,omitempty"         dynamodbav:"totalCollateral,omitempty"`
+	References              []TxIn              `json:"references,omitempty"              dynamodbav:"references,omitempty"`
+	Proposals               []ProposalProcedure `json:"proposals,omitempty"               dynamodbav:"proposals,omitempty"`     // conway
+	Votes                   []VotingProcedure   `json:"votes,omitempty"                   dynamodbav:"votes,omitempty"`         // conway
+	TreasuryValue           *num.Int            `json:"treasuryValue,omitempty"           dynamodbav:"treasuryValue,omitempty"` // conway, current treasury value asserted by the tx
+	Donation                *num.Int            `json:"donation,omitempty"                dynamodbav:"donation,omitempty"`      // conway, treasury donation
 }
 
 type TxID string
@@ -462,11 +450,11 @@ func (t TxIn) TxID() TxID {
 }
 
 type TxOut struct {
-	Address   string          `json:"address,omitempty"   dynamodbav:"address,omitempty"`
-	Datum     string          `json:"datum,omitempty"     dynamodbav:"datum,omitempty"`
-	DatumHash string          `json:"datumHash,omitempty" dynamodbav:"datumHash,omitempty"`
-	Value     Value           `json:"value,omitempty"     dynamodbav:"value,omitempty"`
-	Script    json.RawMessage `json:"script,omitempty"    dynamodbav:"script,omitempty"`
+	Address   string  `json:"address,omitempty"   dynamodbav:"address,omitempty"`
+	Datum     string  `json:"datum,omitempty"     dynamodbav:"datum,omitempty"`
+	DatumHash string  `json:"datumHash,omitempty" dynamodbav:"datumHash,omitempty"`
+	Value     Value   `json:"value,omitempty"     dynamodbav:"value,omitempty"`
+	Script    *Script `json:"script,omitempty"    dynamodbav:"script,omitempty"`
 }
 
 type TxOuts []TxOut
@@ -546,71 +534,13 @@ func (d *Datums) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue)
 type Witness struct {
 	Bootstrap  []json.RawMessage `json:"bootstrap,omitempty"  dynamodbav:"bootstrap,omitempty"`
 	Datums     Datums            `json:"datums,omitempty"     dynamodbav:"datums,omitempty"`
-	Redeemers  json.RawMessage   `json:"redeemers,omitempty"  dynamodbav:"redeemers,omitempty"`
-	Scripts    json.RawMessage   `json:"scripts,omitempty"    dynamodbav:"scripts,omitempty"`
+	Redeemers  Redeemers         `json:"redeemers,omitempty"  dynamodbav:"redeemers,omitempty"`
+	Scripts    Scripts           `json:"scripts,omitempty"    dynamodbav:"scripts,omitempty"`
 	Signatures map[string]string `json:"signatures,omitempty" dynamodbav:"signatures,omitempty"`
 }
 
+// ValidityInterval holds the slot bounds of a tx; a zero bound is unset
 type ValidityInterval struct {
 	InvalidBefore    uint64 `json:"invalidBefore,omitempty"    dynamodbav:"invalidBefore,omitempty"`
 	InvalidHereafter uint64 `json:"invalidHereafter,omitempty" dynamodbav:"invalidHereafter,omitempty"`
 }
-
-type Value struct {
-	Coins  num.Int             `json:"coins,omitempty"  dynamodbav:"coins,omitempty"`
-	Assets map[AssetID]num.Int `json:"assets,omitempty" dynamodbav:"assets,omitempty"`
-}
-
-func Add(a Value, b Value) Value {
-	var result Value
-	result.Coins = a.Coins.Add(b.Coins)
-	result.Assets = map[AssetID]num.Int{}
-	for assetId, amt := range a.Assets {
-		result.Assets[assetId] = amt
-	}
-	for assetId, amt := range b.Assets {
-		result.Assets[assetId] = result.Assets[assetId].Add(amt)
-	}
-	return result
-}
-func Subtract(a Value, b Value) Value {
-	var result Value
-	result.Coins = a.Coins.Sub(b.Coins)
-	result.Assets = map[AssetID]num.Int{}
-	for assetId, amt := range a.Assets {
-		result.Assets[assetId] = amt
-	}
-	for assetId, amt := range b.Assets {
-		result.Assets[assetId] = result.Assets[assetId].Sub(amt)
-	}
-	return result
-}
-func Enough(have Value, want Value) (bool, error) {
-	if have.Coins.Int64() < want.Coins.Int64() {
-		return false, fmt.Errorf("not enough ADA to meet demand")
-	}
-	for asset, amt := range want.Assets {
-		if have.Assets[asset].Int64() < amt.Int64() {
-			return false, fmt.Errorf("not enough %v to meet demand", asset)
-		}
-	}
-	return true, nil
-}
-func Equals(left Value, right Value) bool {
-	if left.Coins.String() != right.Coins.String() {
-		return false
-	}
-	for k, v := range left.Assets {
-		lv := v.Uint64()
-		if lv != 0 && right.Assets[k].Uint64() != lv {
-			return false
-		}
-	}
-	for k, v := range right.Assets {
-		rv := v.Uint64()
-		if rv != 0 && left.Assets[k].Uint64() != rv {
-			return false
-		}
-	}
-	return true
-}
@@ -0,0 +1,231 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// Value is a quantity of lovelace and native assets. Missing assets and
+// assets with a zero quantity are equivalent; all operations are exact
+type Value struct {
+	Coins  num.Int             `json:"coins,omitempty"  dynamodbav:"coins,omitempty"`
+	Assets map[AssetID]num.Int `json:"assets,omitempty" dynamodbav:"assets,omitempty"`
+}
+
+// Add returns a + b with zero entries removed
+func Add(a Value, b Value) Value {
+	return combine(a, b, num.Int.Add)
+}
+
+// Subtract returns a - b with zero entries removed
+func Subtract(a Value, b Value) Value {
+	return combine(a, b, num.Int.Sub)
+}
+
+// Negate returns -v
+func Negate(v Value) Value {
+	return Subtract(Value{}, v)
+}
+
+// Scale returns v with every quantity multiplied by factor
+func Scale(v Value, factor num.Int) Value {
+	result := Value{
+		Coins:  v.Coins.Mul(factor),
+		Assets: map[AssetID]num.Int{},
+	}
+	for assetID, amt := range v.Assets {
+		result.Assets[assetID] = amt.Mul(factor)
+	}
+	return Normalize(result)
+}
+
+// Normalize returns a copy of v without zero quantity assets; Assets is nil
+// when no assets remain
+func Normalize(v Value) Value {
+	result := Value{Coins: v.Coins}
+	for assetID, amt := range v.Assets {
+		if amt.IsZero() {
+			continue
+		}
+		if result.Assets == nil {
+			result.Assets = map[AssetID]num.Int{}
+		}
+		result.Assets[assetID] = amt
+	}
+	return result
+}
+
+// Compare returns -1, 0 or 1 when every quantity of a is respectively less
+// than or equal, equal to, or greater than or equal to the matching quantity
+// of b. Values are only partially ordered; ok is false when a and b are
+// incomparable
+func Compare(a Value, b Value) (cmp int, ok bool) {
+	var less, greater bool
+	visit := func(x, y num.Int) {
+		switch x.Cmp(y) {
+		case -1:
+			less = true
+		case 1:
+			greater = true
+		}
+	}
+
+	visit(a.Coins, b.Coins)
+	for assetID, amt := range a.Assets {
+		visit(amt, b.Assets[assetID])
+	}
+	for assetID, amt := range b.Assets {
+		if _, found := a.Assets[assetID]; !found {
+			visit(num.Int{}, amt)
+		}
+	}
+
+	switch {
+	case less && greater:
+		return 0, false
+	case less:
+		return -1, true
+	case greater:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// GreaterOrEqual returns true if every quantity of a is at least the matching
+// quantity of b
+func GreaterOrEqual(a Value, b Value) bool {
+	cmp, ok := Compare(a, b)
+	return ok && cmp >= 0
+}
+
+// Enough returns an error identifying the first shortfall when have does not
+// cover want
+func Enough(have Value, want Value) (bool, error) {
+	if have.Coins.Cmp(want.Coins) < 0 {
+		return false, fmt.Errorf("not enough ADA to meet demand")
+	}
+	for _, asset := range want.AssetIDs() {
+		if have.Assets[asset].Cmp(want.Assets[asset]) < 0 {
+			return false, fmt.Errorf("not enough %v to meet demand", asset)
+		}
+	}
+	return true, nil
+}
+
+// Equals returns true if left and right hold exactly the same quantities
+func Equals(left Value, right Value) bool {
+	cmp, ok := Compare(left, right)
+	return ok && cmp == 0
+}
+
+// IsZero returns true if v holds no lovelace and no assets
+func (v Value) IsZero() bool {
+	return Equals(v, Value{})
+}
+
+// ADA returns only the lovelace portion of v
+func (v Value) ADA() Value {
+	return Value{Coins: v.Coins}
+}
+
+// NativeAssets returns v without its lovelace
+func (v Value) NativeAssets() Value {
+	return Normalize(Value{Assets: v.Assets})
+}
+
+// Split returns the lovelace and native asset portions of v
+func (v Value) Split() (ada Value, assets Value) {
+	return v.ADA(), v.NativeAssets()
+}
+
+// FilterPolicy returns the assets of v minted by policyID, without lovelace
+func (v Value) FilterPolicy(policyID string) Value {
+	return v.Filter(func(assetID AssetID) bool { return assetID.PolicyID() == policyID })
+}
+
+// Filter returns the assets of v for which keep returns true, without lovelace
+func (v Value) Filter(keep func(AssetID) bool) Value {
+	var result Value
+	for assetID, amt := range v.Assets {
+		if !keep(assetID) {
+			continue
+		}
+		if result.Assets == nil {
+			result.Assets = map[AssetID]num.Int{}
+		}
+		result.Assets[assetID] = amt
+	}
+	return Normalize(result)
+}
+
+// PolicyIDs returns the distinct policy ids held by v in canonical order
+func (v Value) PolicyIDs() []string {
+	var policies []string
+	for _, assetID := range v.AssetIDs() {
+		if n := len(policies); n == 0 || policies[n-1] != assetID.PolicyID() {
+			policies = append(policies, assetID.PolicyID())
+		}
+	}
+	return policies
+}
+
+// AssetIDs returns the non-zero assets of v in canonical ledger order; by
+// policy id, then by asset name length, then by asset name
+func (v Value) AssetIDs() []AssetID {
+	var assetIDs []AssetID
+	for assetID, amt := range v.Assets {
+		if !amt.IsZero() {
+			assetIDs = append(assetIDs, assetID)
+		}
+	}
+	sort.Slice(assetIDs, func(i, j int) bool {
+		return lessAssetID(assetIDs[i], assetIDs[j])
+	})
+	return assetIDs
+}
+
+// lessAssetID orders asset ids as canonical cbor orders their byte keys
+func lessAssetID(a, b AssetID) bool {
+	if pa, pb := a.PolicyID(), b.PolicyID(); pa != pb {
+		if len(pa) != len(pb) {
+			return len(pa) < len(pb)
+		}
+		return pa < pb
+	}
+	na, nb := a.AssetName(), b.AssetName()
+	if len(na) != len(nb) {
+		return len(na) < len(nb)
+	}
+	return na < nb
+}
+
+func combine(a, b Value, fn func(x, y num.Int) num.Int) Value {
+	result := Value{
+		Coins:  fn(a.Coins, b.Coins),
+		Assets: map[AssetID]num.Int{},
+	}
+	for assetID, amt := range a.Assets {
+		result.Assets[assetID] = amt
+	}
+	for assetID, amt := range b.Assets {
+		result.Assets[assetID] = fn(result.Assets[assetID], amt)
+	}
+	return Normalize(result)
+}
@@ -0,0 +1,191 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// arbitraryValue generates values with quantities well beyond int64 and a
+// small asset universe so that generated values share assets
+type arbitraryValue Value
+
+func (arbitraryValue) Generate(r *rand.Rand, _ int) reflect.Value {
+	quantity := func() num.Int {
+		n := new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), 100))
+		if r.Intn(2) == 0 {
+			n.Neg(n)
+		}
+		return num.Int(*n)
+	}
+
+	v := arbitraryValue{Coins: quantity(), Assets: map[AssetID]num.Int{}}
+	for _, assetID := range []AssetID{"a.01", "a.02", "b", "c.0102"} {
+		switch r.Intn(3) {
+		case 0:
+			v.Assets[assetID] = quantity()
+		case 1:
+			v.Assets[assetID] = num.Int64(0)
+		}
+	}
+	return reflect.ValueOf(v)
+}
+
+func TestValue_Properties(t *testing.T) {
+	properties := map[string]interface{}{
+		"add commutes": func(a, b arbitraryValue) bool {
+			return Equals(Add(Value(a), Value(b)), Add(Value(b), Value(a)))
+		},
+		"add associates": func(a, b, c arbitraryValue) bool {
+			return Equals(
+				Add(Add(Value(a), Value(b)), Value(c)),
+				Add(Value(a), Add(Value(b), Value(c))),
+			)
+		},
+		"subtract inverts add": func(a, b arbitraryValue) bool {
+			return Equals(Subtract(Add(Value(a), Value(b)), Value(b)), Value(a))
+		},
+		"negate is subtract from zero": func(a arbitraryValue) bool {
+			return Add(Value(a), Negate(Value(a))).IsZero() &&
+				Equals(Negate(Negate(Value(a))), Value(a))
+		},
+		"scale distributes": func(a, b arbitraryValue, k int64) bool {
+			factor := num.Int64(k)
+			return Equals(
+				Scale(Add(Value(a), Value(b)), factor),
+				Add(Scale(Value(a), factor), Scale(Value(b), factor)),
+			)
+		},
+		"compare is antisymmetric": func(a, b arbitraryValue) bool {
+			ab, okab := Compare(Value(a), Value(b))
+			ba, okba := Compare(Value(b), Value(a))
+			return okab == okba && ab == -ba
+		},
+		"compare matches difference": func(a, b arbitraryValue) bool {
+			diff := Subtract(Value(a), Value(b))
+			return GreaterOrEqual(Value(a), Value(b)) == GreaterOrEqual(diff, Value{})
+		},
+		"normalize drops zeros": func(a arbitraryValue) bool {
+			for _, amt := range Normalize(Value(a)).Assets {
+				if amt.IsZero() {
+					return false
+				}
+			}
+			return Equals(Normalize(Value(a)), Value(a))
+		},
+		"split recombines": func(a arbitraryValue) bool {
+			ada, assets := Value(a).Split()
+			return Equals(Add(ada, assets), Value(a))
+		},
+	}
+
+	for name, fn := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(fn, nil); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+		})
+	}
+}
+
+func TestValue_Equals(t *testing.T) {
+	assert.True(t, Equals(Value{Coins: num.Uint64(0)}, Value{Coins: num.Uint64(0)}))
+	assert.True(t, Equals(
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(0)}},
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(0)}},
+	))
+	assert.True(t, Equals(
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(15)}},
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(15)}},
+	))
+	assert.False(t, Equals(Value{Coins: num.Uint64(0)}, Value{Coins: num.Uint64(1)}))
+	assert.False(t, Equals(
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(0)}},
+		Value{Coins: num.Uint64(1)},
+	))
+	assert.False(t, Equals(
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(10)}},
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(15)}},
+	))
+}
+
+func TestValue_BigQuantities(t *testing.T) {
+	big1, _ := num.New("18446744073709551616") // 2^64
+	big2, _ := num.New("18446744073709551617")
+
+	have := Value{Coins: num.Int64(1), Assets: map[AssetID]num.Int{"a.01": big1}}
+	want := Value{Coins: num.Int64(1), Assets: map[AssetID]num.Int{"a.01": big2}}
+
+	ok, err := Enough(have, want)
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+
+	ok, err = Enough(want, have)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	assert.False(t, Equals(have, want))
+	assert.False(t, Equals(
+		Value{Assets: map[AssetID]num.Int{"a.01": num.Int64(-5)}},
+		Value{Assets: map[AssetID]num.Int{"a.01": num.Int64(5)}},
+	))
+}
+
+func TestValue_Normalize(t *testing.T) {
+	got := Subtract(
+		Value{Coins: num.Int64(5), Assets: map[AssetID]num.Int{"a.01": num.Int64(3), "b": num.Int64(2)}},
+		Value{Coins: num.Int64(5), Assets: map[AssetID]num.Int{"a.01": num.Int64(3)}},
+	)
+	assert.True(t, Equals(Value{Assets: map[AssetID]num.Int{"b": num.Int64(2)}}, got))
+	assert.Len(t, got.Assets, 1)
+}
+
+func TestValue_Compare(t *testing.T) {
+	a := Value{Coins: num.Int64(5), Assets: map[AssetID]num.Int{"a.01": num.Int64(3)}}
+	b := Value{Coins: num.Int64(6)}
+
+	_, ok := Compare(a, b)
+	assert.False(t, ok)
+	assert.False(t, GreaterOrEqual(a, b))
+	assert.False(t, GreaterOrEqual(b, a))
+
+	cmp, ok := Compare(a, Add(a, b))
+	assert.True(t, ok)
+	assert.Equal(t, -1, cmp)
+}
+
+func TestValue_AssetIDs(t *testing.T) {
+	v := Value{
+		Assets: map[AssetID]num.Int{
+			"bb.01":   num.Int64(1),
+			"aa.0102": num.Int64(1),
+			"aa.02":   num.Int64(1),
+			"aa":      num.Int64(1),
+			"aa.03":   num.Int64(0),
+		},
+	}
+	assert.Equal(t, []AssetID{"aa", "aa.02", "aa.0102", "bb.01"}, v.AssetIDs())
+	assert.Equal(t, []string{"aa", "bb"}, v.PolicyIDs())
+
+	assert.Equal(t, Value{Assets: map[AssetID]num.Int{"bb.01": num.Int64(1)}}, v.FilterPolicy("bb"))
+}
@@ -0,0 +1,271 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package network provides the parameters of the public cardano networks and
+// offline slot and time conversion based upon them
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/address"
+)
+
+// Network describes a cardano network that starts in byron and transitions to
+// shelley, after which the slot length and epoch length remain fixed
+type Network struct {
+	Name                   string
+	Magic                  uint32
+	NetworkID              byte // address network id, address.Mainnet or address.Testnet
+	SystemStart            time.Time
+	ByronSlotLength        time.Duration
+	ByronEpochLength       uint64 // ten times the security parameter
+	ShelleySlotLength      time.Duration
+	ShelleyEpochLength     uint64
+	ShelleyTransitionEpoch uint64 // first shelley epoch; 0 if the network began in shelley or later
+}
+
+var (
+	Mainnet = Network{
+		Name:                   "mainnet",
+		Magic:                  764824073,
+		NetworkID:              address.Mainnet,
+		SystemStart:            time.Date(2017, 9, 23, 21, 44, 51, 0, time.UTC),
+		ByronSlotLength:        20 * time.Second,
+		ByronEpochLength:       21600,
+		ShelleySlotLength:      time.Second,
+		ShelleyEpochLength:     432000,
+		ShelleyTransitionEpoch: 208,
+	}
+	Preprod = Network{
+		Name:                   "preprod",
+		Magic:                  1,
+		NetworkID:              address.Testnet,
+		SystemStart:            time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		ByronSlotLength:        20 * time.Second,
+		ByronEpochLength:       21600,
+		ShelleySlotLength:      time.Second,
+		ShelleyEpochLength:     432000,
+		ShelleyTransitionEpoch: 4,
+	}
+	Preview = Network{
+		Name:                   "preview",
+		Magic:                  2,
+		NetworkID:              address.Testnet,
+		SystemStart:            time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC),
+		ByronSlotLength:        20 * time.Second,
+		ByronEpochLength:       4320,
+		ShelleySlotLength:      time.Second,
+		ShelleyEpochLength:     86400,
+		ShelleyTransitionEpoch: 0,
+	}
+)
+
+// Networks lists the presets
+var Networks = []Network{Mainnet, Preprod, Preview}
+
+// ByName returns the preset with the provided name, ignoring case
+func ByName(name string) (Network, bool) {
+	for _, n := range Networks {
+		if strings.EqualFold(n.Name, name) {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// ByMagic returns the preset with the provided network magic
+func ByMagic(magic uint32) (Network, bool) {
+	for _, n := range Networks {
+		if n.Magic == magic {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+func (n Network) String() string {
+	return n.Name
+}
+
+// ShelleyStartSlot returns the first slot of the shelley era
+func (n Network) ShelleyStartSlot() uint64 {
+	return n.ShelleyTransitionEpoch * n.ByronEpochLength
+}
+
+// ShelleyStartTime returns the start of the shelley era
+func (n Network) ShelleyStartTime() time.Time {
+	return n.SystemStart.Add(time.Duration(n.ShelleyStartSlot()) * n.ByronSlotLength)
+}
+
+// SlotToTime returns the start of the slot
+func (n Network) SlotToTime(slot uint64) time.Time {
+	if shelley := n.ShelleyStartSlot(); slot >= shelley {
+		return n.ShelleyStartTime().Add(time.Duration(slot-shelley) * n.ShelleySlotLength)
+	}
+	return n.SystemStart.Add(time.Duration(slot) * n.ByronSlotLength)
+}
+
+// TimeToSlot returns the slot containing t
+func (n Network) TimeToSlot(t time.Time) (uint64, error) {
+	if t.Before(n.SystemStart) {
+		return 0, fmt.Errorf("time %v precedes %v system start %v", t, n.Name, n.SystemStart)
+	}
+	if n.ByronSlotLength <= 0 || n.ShelleySlotLength <= 0 {
+		return 0, fmt.Errorf("invalid %v network: slot length must be positive", n.Name)
+	}
+
+	if shelley := n.ShelleyStartTime(); !t.Before(shelley) {
+		return n.ShelleyStartSlot() + uint64(t.Sub(shelley)/n.ShelleySlotLength), nil
+	}
+	return uint64(t.Sub(n.SystemStart) / n.ByronSlotLength), nil
+}
+
+// SlotToEpoch returns the epoch containing the slot
+func (n Network) SlotToEpoch(slot uint64) uint64 {
+	if shelley := n.ShelleyStartSlot(); slot >= shelley {
+		return n.ShelleyTransitionEpoch + (slot-shelley)/n.ShelleyEpochLength
+	}
+	return slot / n.ByronEpochLength
+}
+
+// EpochFirstSlot returns the first slot of the epoch
+func (n Network) EpochFirstSlot(epoch uint64) uint64 {
+	if epoch >= n.ShelleyTransitionEpoch {
+		return n.ShelleyStartSlot() + (epoch-n.ShelleyTransitionEpoch)*n.ShelleyEpochLength
+	}
+	return epoch * n.ByronEpochLength
+}
+
+// ValidateAddress returns an error if the bech32 or base58 encoded address
+// does not belong to the network.  Byron addresses outside of mainnet carry
+// the network magic
+func (n Network) ValidateAddress(s string) error {
+	addr, err := address.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	if addr.Byron != nil {
+		switch magic := addr.Byron.ProtocolMagic; {
+		case magic == nil && n.NetworkID == address.Mainnet:
+			return nil
+		case magic != nil && *magic == n.Magic:
+			return nil
+		default:
+			return fmt.Errorf("byron address, %v, does not belong to %v", s, n.Name)
+		}
+	}
+
+	if addr.Network != n.NetworkID {
+		return fmt.Errorf("address, %v, has network id %v; want %v for %v", s, addr.Network, n.NetworkID, n.Name)
+	}
+	return nil
+}
+
+type byronGenesis struct {
+	BlockVersionData struct {
+		SlotDuration string `json:"slotDuration"` // milliseconds
+	} `json:"blockVersionData"`
+	ProtocolConsts struct {
+		K             uint64 `json:"k"`
+		ProtocolMagic uint32 `json:"protocolMagic"`
+	} `json:"protocolConsts"`
+	StartTime int64 `json:"startTime"` // unix seconds
+}
+
+type shelleyGenesis struct {
+	EpochLength  uint64    `json:"epochLength"`
+	NetworkID    string    `json:"networkId"`
+	NetworkMagic uint32    `json:"networkMagic"`
+	SlotLength   float64   `json:"slotLength"` // seconds
+	SystemStart  time.Time `json:"systemStart"`
+}
+
+// FromGenesis builds a network from the contents of its byron and shelley
+// genesis files.  The transition epoch is not part of the genesis; it is the
+// first shelley epoch.  byron may be nil for networks that start in shelley
+func FromGenesis(name string, byron, shelley []byte, transitionEpoch uint64) (Network, error) {
+	var sg shelleyGenesis
+	if err := json.Unmarshal(shelley, &sg); err != nil {
+		return Network{}, fmt.Errorf("failed to decode shelley genesis: %w", err)
+	}
+	if sg.SlotLength <= 0 || sg.EpochLength == 0 {
+		return Network{}, fmt.Errorf("invalid shelley genesis: slot length and epoch length required")
+	}
+
+	n := Network{
+		Name:                   name,
+		Magic:                  sg.NetworkMagic,
+		NetworkID:              address.Testnet,
+		SystemStart:            sg.SystemStart.UTC(),
+		ShelleySlotLength:      time.Duration(math.Round(sg.SlotLength * float64(time.Second))),
+		ShelleyEpochLength:     sg.EpochLength,
+		ShelleyTransitionEpoch: transitionEpoch,
+	}
+	if strings.EqualFold(sg.NetworkID, "mainnet") {
+		n.NetworkID = address.Mainnet
+	}
+
+	if byron == nil {
+		if transitionEpoch > 0 {
+			return Network{}, fmt.Errorf("byron genesis required for transition epoch %v", transitionEpoch)
+		}
+		return n, nil
+	}
+
+	var bg byronGenesis
+	if err := json.Unmarshal(byron, &bg); err != nil {
+		return Network{}, fmt.Errorf("failed to decode byron genesis: %w", err)
+	}
+	millis, err := strconv.ParseUint(bg.BlockVersionData.SlotDuration, 10, 64)
+	if err != nil {
+		return Network{}, fmt.Errorf("failed to decode byron genesis slot duration, %v: %w", bg.BlockVersionData.SlotDuration, err)
+	}
+	if bg.ProtocolConsts.ProtocolMagic != n.Magic {
+		return Network{}, fmt.Errorf("byron protocol magic, %v, does not match shelley network magic, %v", bg.ProtocolConsts.ProtocolMagic, n.Magic)
+	}
+	if start := time.Unix(bg.StartTime, 0).UTC(); !start.Equal(n.SystemStart) {
+		return Network{}, fmt.Errorf("byron start time, %v, does not match shelley system start, %v", start, n.SystemStart)
+	}
+
+	n.ByronSlotLength = time.Duration(millis) * time.Millisecond
+	n.ByronEpochLength = 10 * bg.ProtocolConsts.K
+	return n, nil
+}
+
+// LoadGenesis reads the byron and shelley genesis files, see FromGenesis.  An
+// empty byronPath skips the byron genesis
+func LoadGenesis(name, byronPath, shelleyPath string, transitionEpoch uint64) (Network, error) {
+	var byron []byte
+	if byronPath != "" {
+		data, err := os.ReadFile(byronPath)
+		if err != nil {
+			return Network{}, fmt.Errorf("failed to read byron genesis, %v: %w", byronPath, err)
+		}
+		byron = data
+	}
+
+	shelley, err := os.ReadFile(shelleyPath)
+	if err != nil {
+		return Network{}, fmt.Errorf("failed to read shelley genesis, %v: %w", shelleyPath, err)
+	}
+	return FromGenesis(name, byron, shelley, transitionEpoch)
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetwork_SlotToTime(t *testing.T) {
+	testCases := map[string]struct {
+		network Network
+		slot    uint64
+		time    time.Time
+		epoch   uint64
+	}{
+		"mainnet byron": {
+			network: Mainnet,
+			slot:    21600,
+			time:    time.Date(2017, 9, 28, 21, 44, 51, 0, time.UTC),
+			epoch:   1,
+		},
+		"mainnet shelley": {
+			network: Mainnet,
+			slot:    4492800,
+			time:    time.Date(2020, 7, 29, 21, 44, 51, 0, time.UTC),
+			epoch:   208,
+		},
+		"mainnet later": {
+			network: Mainnet,
+			slot:    4924800,
+			time:    time.Date(2020, 8, 3, 21, 44, 51, 0, time.UTC),
+			epoch:   209,
+		},
+		"preprod shelley": {
+			network: Preprod,
+			slot:    86400,
+			time:    time.Date(2022, 6, 21, 0, 0, 0, 0, time.UTC),
+			epoch:   4,
+		},
+		"preview": {
+			network: Preview,
+			slot:    86400,
+			time:    time.Date(2022, 10, 26, 0, 0, 0, 0, time.UTC),
+			epoch:   1,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := tc.network.SlotToTime(tc.slot)
+			assert.True(t, tc.time.Equal(got), "got %v; want %v", got, tc.time)
+
+			slot, err := tc.network.TimeToSlot(got.Add(time.Millisecond))
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			assert.Equal(t, tc.slot, slot)
+
+			assert.Equal(t, tc.epoch, tc.network.SlotToEpoch(tc.slot))
+			assert.Equal(t, tc.slot, tc.network.EpochFirstSlot(tc.epoch))
+		})
+	}
+
+	_, err := Mainnet.TimeToSlot(Mainnet.SystemStart.Add(-time.Second))
+	assert.NotNil(t, err)
+}
+
+func TestLookup(t *testing.T) {
+	n, ok := ByName("PreProd")
+	assert.True(t, ok)
+	assert.Equal(t, Preprod, n)
+
+	n, ok = ByMagic(764824073)
+	assert.True(t, ok)
+	assert.Equal(t, Mainnet, n)
+
+	_, ok = ByMagic(42)
+	assert.False(t, ok)
+}
+
+func TestNetwork_ValidateAddress(t *testing.T) {
+	const (
+		mainnet      = "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"
+		testnet      = "addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz"
+		byronMainnet = "Ae2tdPwUPEZFRbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAi"
+		byronTestnet = "37btjrVyb4KEB2STADSsj3MYSAdj52X5FrFWpw2r7Wmj2GDzXjFRsHWuZqrw7zSkwopv8Ci3VWeg6bisU9dgJxW5hb2MZYeduNKbQJrqz3zVBsu9nT"
+	)
+
+	assert.Nil(t, Mainnet.ValidateAddress(mainnet))
+	assert.Nil(t, Mainnet.ValidateAddress(byronMainnet))
+	assert.NotNil(t, Mainnet.ValidateAddress(testnet))
+	assert.NotNil(t, Mainnet.ValidateAddress(byronTestnet))
+
+	assert.Nil(t, Preprod.ValidateAddress(testnet))
+	assert.NotNil(t, Preprod.ValidateAddress(mainnet))
+	assert.NotNil(t, Preprod.ValidateAddress(byronTestnet)) // different magic
+
+	legacy := Network{Name: "testnet", Magic: 1097911063}
+	assert.Nil(t, legacy.ValidateAddress(byronTestnet))
+
+	assert.NotNil(t, Mainnet.ValidateAddress("bogus"))
+}
+
+func TestFromGenesis(t *testing.T) {
+	byron := []byte(`{
+		"blockVersionData": {"slotDuration": "20000"},
+		"protocolConsts": {"k": 2160, "protocolMagic": 1},
+		"startTime": 1654041600
+	}`)
+	shelley := []byte(`{
+		"epochLength": 432000,
+		"networkId": "Testnet",
+		"networkMagic": 1,
+		"slotLength": 1,
+		"systemStart": "2022-06-01T00:00:00Z"
+	}`)
+
+	got, err := FromGenesis("preprod", byron, shelley, 4)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, Preprod, got)
+
+	_, err = FromGenesis("preprod", nil, shelley, 4)
+	assert.NotNil(t, err)
+
+	_, err = FromGenesis("preprod", []byte(`{"protocolConsts":{"protocolMagic":2},"blockVersionData":{"slotDuration":"20000"}}`), shelley, 4)
+	assert.NotNil(t, err)
+
+	preview, err := FromGenesis("preview", nil, shelley, 0)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 0, preview.ShelleyStartSlot())
+
+	_, err = LoadGenesis("missing", "", "testdata/missing.json", 0)
+	assert.NotNil(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 )
 
 type EraStart struct {
@@ -56,3 +57,57 @@ func (u *Utxo) UnmarshalJSON(data []byte) (err error) {
 
 	return nil
 }
+
+// PoolDistribution is the share of the active stake delegated to a pool
+type PoolDistribution struct {
+	Stake chainsync.Ratio `json:"stake"`
+	VRF   string          `json:"vrf"`
+}
+
+// StakeDistribution maps pool id to its share of the active stake
+type StakeDistribution map[string]PoolDistribution
+
+// DelegationAndRewards describes a stake credential
+type DelegationAndRewards struct {
+	Delegate string   `json:"delegate,omitempty"` // pool id; empty if not delegated
+	Rewards  *num.Int `json:"rewards,omitempty"`  // nil if not registered
+}
+
+// NonMyopicMemberRewards maps each input, a lovelace amount or stake
+// credential, to the rewards it would earn by pool id
+type NonMyopicMemberRewards map[string]map[string]num.Int
+
+// RewardsProvenance describes how rewards were computed for the last epoch
+type RewardsProvenance struct {
+	DesiredNumberOfPools uint64                           `json:"desiredNumberOfPools"`
+	PoolInfluence        chainsync.Ratio                  `json:"poolInfluence"`
+	TotalRewards         num.Int                          `json:"totalRewards"`
+	ActiveStake          num.Int                          `json:"activeStake"`
+	Pools                map[string]PoolRewardsProvenance `json:"pools"`
+}
+
+type PoolRewardsProvenance struct {
+	Stake                  num.Int `json:"stake"`
+	ApproximatePerformance float64 `json:"approximatePerformance"`
+	PoolParameters         struct {
+		Cost   num.Int         `json:"cost"`
+		Margin chainsync.Ratio `json:"margin"`
+		Pledge num.Int         `json:"pledge"`
+	} `json:"poolParameters"`
+}
+
+// GenesisConfig holds the shelley genesis of the network
+type GenesisConfig struct {
+	SystemStart            time.Time                    `json:"systemStart"`
+	NetworkMagic           uint32                       `json:"networkMagic"`
+	Network                string                       `json:"network"`
+	ActiveSlotsCoefficient chainsync.Ratio              `json:"activeSlotsCoefficient"`
+	SecurityParameter      uint64                       `json:"securityParameter"`
+	EpochLength            uint64                       `json:"epochLength"`
+	SlotsPerKesPeriod      uint64                       `json:"slotsPerKesPeriod"`
+	MaxKesEvolutions       uint64                       `json:"maxKesEvolutions"`
+	SlotLength             uint64                       `json:"slotLength"` // seconds
+	UpdateQuorum           uint64                       `json:"updateQuorum"`
+	MaxLovelaceSupply      num.Int                      `json:"maxLovelaceSupply"`
+	ProtocolParameters     chainsync.ProtocolParameters `json:"protocolParameters"`
+}
@@ -19,8 +19,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
 )
 
@@ -37,6 +39,21 @@ func (c *Client) ChainTip(ctx context.Context) (chainsync.Point, error) {
 	return content.Result, nil
 }
 
+// NetworkTip returns the tip of the node's chain, including its block number,
+// which may be ahead of the ledger tip returned by ChainTip
+func (c *Client) NetworkTip(ctx context.Context) (chainsync.Point, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "chainTip"})
+		content struct{ Result chainsync.Point }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return chainsync.Point{}, fmt.Errorf("failed to query network tip: %w", err)
+	}
+
+	return content.Result, nil
+}
+
 func (c *Client) CurrentEpoch(ctx context.Context) (uint64, error) {
 	var (
 		payload = makePayload("Query", Map{"query": "currentEpoch"})
@@ -50,14 +67,14 @@ func (c *Client) CurrentEpoch(ctx context.Context) (uint64, error) {
 	return content.Result, nil
 }
 
-func (c *Client) CurrentProtocolParameters(ctx context.Context) (json.RawMessage, error) {
+func (c *Client) CurrentProtocolParameters(ctx context.Context) (chainsync.ProtocolParameters, error) {
 	var (
 		payload = makePayload("Query", Map{"query": "currentProtocolParameters"})
-		content struct{ Result json.RawMessage }
+		content struct{ Result chainsync.ProtocolParameters }
 	)
 
 	if err := c.query(ctx, payload, &content); err != nil {
-		return nil, err
+		return chainsync.ProtocolParameters{}, fmt.Errorf("failed to query current protocol parameters: %w", err)
 	}
 
 	return content.Result, nil
@@ -74,14 +91,14 @@ type EraSummary struct {
 }
 
 type EraBound struct {
-	Time  big.Int `json:"time"` // Picosecond precision, too big for uint64
+	Time  big.Int `json:"time"` // Picoseconds since system start, too big for uint64
 	Slot  uint64  `json:"slot"`
 	Epoch uint64  `json:"epoch"`
 }
 
 type EraParameters struct {
 	EpochLength uint64 `json:"epochLength"`
-	SlotLength  uint64 `json:"slotLength"`
+	SlotLength  uint64 `json:"slotLength"` // seconds
 	SafeZone    uint64 `json:"safeZone"`
 }
 
@@ -143,3 +160,185 @@ func (c *Client) UtxosByTxIn(ctx context.Context, txIns ...chainsync.TxIn) ([]st
 
 	return content.Result, nil
 }
+
+// Utxos returns the entire utxo set; this is expensive on mainnet
+func (c *Client) Utxos(ctx context.Context) ([]statequery.Utxo, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "utxo"})
+		content struct{ Result []statequery.Utxo }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query utxos: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// BlockHeight returns the height of the most recent block; 0 at origin
+func (c *Client) BlockHeight(ctx context.Context) (uint64, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "blockHeight"})
+		content struct{ Result json.RawMessage }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return 0, fmt.Errorf("failed to query block height: %w", err)
+	}
+
+	if string(content.Result) == `"origin"` {
+		return 0, nil
+	}
+
+	var height uint64
+	if err := json.Unmarshal(content.Result, &height); err != nil {
+		return 0, fmt.Errorf("failed to decode block height, %v: %w", string(content.Result), err)
+	}
+
+	return height, nil
+}
+
+func (c *Client) SystemStart(ctx context.Context) (time.Time, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "systemStart"})
+		content struct{ Result time.Time }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return time.Time{}, fmt.Errorf("failed to query system start: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+func (c *Client) GenesisConfig(ctx context.Context) (statequery.GenesisConfig, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "genesisConfig"})
+		content struct{ Result statequery.GenesisConfig }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return statequery.GenesisConfig{}, fmt.Errorf("failed to query genesis config: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// ProposedProtocolParameters returns the protocol parameter updates proposed
+// for the next epoch keyed by genesis delegate key hash
+func (c *Client) ProposedProtocolParameters(ctx context.Context) (map[string]chainsync.ProtocolParameters, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "proposedProtocolParameters"})
+		content struct {
+			Result map[string]chainsync.ProtocolParameters
+		}
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query proposed protocol parameters: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+func (c *Client) StakeDistribution(ctx context.Context) (statequery.StakeDistribution, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "stakeDistribution"})
+		content struct{ Result statequery.StakeDistribution }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query stake distribution: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// DelegationsAndRewards returns the delegation and reward balance of each
+// stake credential, hex encoded key hash or bech32 stake address
+func (c *Client) DelegationsAndRewards(ctx context.Context, credentials ...string) (map[string]statequery.DelegationAndRewards, error) {
+	var (
+		payload = makePayload("Query", Map{"query": Map{"delegationsAndRewards": credentials}})
+		content struct {
+			Result map[string]statequery.DelegationAndRewards
+		}
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query delegations and rewards: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// PoolIDs returns the bech32 ids of the registered stake pools
+func (c *Client) PoolIDs(ctx context.Context) ([]string, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "poolIds"})
+		content struct{ Result []string }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query pool ids: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// PoolParameters returns the registered parameters of the provided pools
+// keyed by pool id
+func (c *Client) PoolParameters(ctx context.Context, poolIDs ...string) (map[string]chainsync.PoolParameters, error) {
+	var (
+		payload = makePayload("Query", Map{"query": Map{"poolParameters": poolIDs}})
+		content struct {
+			Result map[string]chainsync.PoolParameters
+		}
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query pool parameters: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// NonMyopicMemberRewards returns the rewards each lovelace amount or stake
+// credential would earn by delegating to each pool
+func (c *Client) NonMyopicMemberRewards(ctx context.Context, amounts []num.Int, credentials []string) (statequery.NonMyopicMemberRewards, error) {
+	inputs := make([]interface{}, 0, len(amounts)+len(credentials))
+	for _, amount := range amounts {
+		inputs = append(inputs, amount)
+	}
+	for _, credential := range credentials {
+		inputs = append(inputs, credential)
+	}
+
+	var (
+		payload = makePayload("Query", Map{"query": Map{"nonMyopicMemberRewards": inputs}})
+		content struct {
+			Result statequery.NonMyopicMemberRewards
+		}
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query non-myopic member rewards: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// RewardsProvenance queries rewardsProvenance'.  The trailing prime is part of
+// the ogmios v5 query name; the unprimed rewardsProvenance is a different,
+// deprecated query with another result shape
+func (c *Client) RewardsProvenance(ctx context.Context) (statequery.RewardsProvenance, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "rewardsProvenance'"})
+		content struct{ Result statequery.RewardsProvenance }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return statequery.RewardsProvenance{}, fmt.Errorf("failed to query rewards provenance: %w", err)
+	}
+
+	return content.Result, nil
+}
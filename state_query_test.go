@@ -18,9 +18,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 )
 
 func TestClient_ChainTip(t *testing.T) {
@@ -138,3 +146,167 @@ func TestClient_UtxosByAddress(t *testing.T) {
 	encoder.SetIndent("", "  ")
 	_ = encoder.Encode(utxos)
 }
+
+// queryServer answers each Query with the result registered for its name and
+// records the arguments received
+func queryServer(t *testing.T, results map[string]string) (*Client, map[string]json.RawMessage) {
+	var (
+		upgrader = websocket.Upgrader{}
+		received = map[string]json.RawMessage{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		var request struct {
+			Args struct{ Query json.RawMessage }
+		}
+		if err := c.ReadJSON(&request); err != nil {
+			return
+		}
+
+		name := strings.Trim(string(request.Args.Query), `"`)
+		var args map[string]json.RawMessage
+		if json.Unmarshal(request.Args.Query, &args) == nil {
+			for key, value := range args {
+				name = key
+				received[key] = value
+			}
+		}
+
+		result, ok := results[name]
+		if !ok {
+			t.Errorf("unexpected query, %v", name)
+			return
+		}
+		_ = c.WriteMessage(websocket.TextMessage, []byte(`{"type":"jsonwsp/response","result":`+result+`}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(WithEndpoint("ws" + strings.TrimPrefix(server.URL, "http")))
+	return client, received
+}
+
+func TestClient_LedgerStateQueries(t *testing.T) {
+	const (
+		poolID = "pool1z5uqdk7dzdxaae5633fqfcu2eqzy3a3rgtuvy087fdld7yws0xt"
+		hash   = "d3b0f3f4e1e5c0c2f7d8f1e5c0c2f7d8f1e5c0c2f7d8f1e5c0c2f7d8f1e5c0c2"
+	)
+
+	client, received := queryServer(t, map[string]string{
+		"chainTip":                   `{"slot":10,"hash":"` + hash + `","blockNo":3}`,
+		"ledgerTip":                  `{"slot":10,"hash":"` + hash + `"}`,
+		"blockHeight":                `3`,
+		"systemStart":                `"2022-06-01T00:00:00Z"`,
+		"genesisConfig":              `{"systemStart":"2022-06-01T00:00:00Z","networkMagic":1,"network":"testnet","activeSlotsCoefficient":"1/20","securityParameter":2160,"epochLength":432000,"slotLength":1,"maxLovelaceSupply":45000000000000000}`,
+		"proposedProtocolParameters": `{"abc":{"minFeeCoefficient":44}}`,
+		"stakeDistribution":          `{"` + poolID + `":{"stake":"1/1000","vrf":"` + hash + `"}}`,
+		"delegationsAndRewards":      `{"abc":{"delegate":"` + poolID + `","rewards":1000}}`,
+		"poolIds":                    `["` + poolID + `"]`,
+		"poolParameters":             `{"` + poolID + `":{"id":"` + poolID + `","vrf":"` + hash + `","pledge":100,"cost":340000000,"margin":"1/100"}}`,
+		"nonMyopicMemberRewards":     `{"1000000":{"` + poolID + `":42}}`,
+		"rewardsProvenance'":         `{"desiredNumberOfPools":500,"poolInfluence":"3/10","totalRewards":100,"activeStake":200,"pools":{"` + poolID + `":{"stake":5,"approximatePerformance":0.5,"poolParameters":{"cost":1,"margin":"1/10","pledge":2}}}}`,
+		"utxo":                       `[[{"txId":"` + hash + `","index":0},{"address":"addr_test1","value":{"coins":5}}]]`,
+	})
+	ctx := context.Background()
+
+	tip, err := client.ChainTip(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	ps, _ := tip.PointStruct()
+	assert.EqualValues(t, 10, ps.Slot)
+	assert.EqualValues(t, 0, ps.BlockNo)
+
+	tip, err = client.NetworkTip(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	ps, _ = tip.PointStruct()
+	assert.EqualValues(t, 3, ps.BlockNo)
+
+	height, err := client.BlockHeight(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 3, height)
+
+	start, err := client.SystemStart(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.True(t, start.Equal(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)))
+
+	genesis, err := client.GenesisConfig(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 1, genesis.NetworkMagic)
+	assert.Equal(t, "1/20", genesis.ActiveSlotsCoefficient.String())
+	assert.Equal(t, "45000000000000000", genesis.MaxLovelaceSupply.String())
+
+	proposed, err := client.ProposedProtocolParameters(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 44, *proposed["abc"].MinFeeCoefficient)
+
+	distribution, err := client.StakeDistribution(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, "1/1000", distribution[poolID].Stake.String())
+
+	delegations, err := client.DelegationsAndRewards(ctx, "abc")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, poolID, delegations["abc"].Delegate)
+	assert.Equal(t, "1000", delegations["abc"].Rewards.String())
+	assert.JSONEq(t, `["abc"]`, string(received["delegationsAndRewards"]))
+
+	poolIDs, err := client.PoolIDs(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, []string{poolID}, poolIDs)
+
+	pools, err := client.PoolParameters(ctx, poolID)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, "1/100", pools[poolID].Margin.String())
+
+	rewards, err := client.NonMyopicMemberRewards(ctx, []num.Int{num.Int64(1000000)}, []string{"abc"})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Equal(t, "42", rewards["1000000"][poolID].String())
+	assert.JSONEq(t, `[1000000,"abc"]`, string(received["nonMyopicMemberRewards"]))
+
+	provenance, err := client.RewardsProvenance(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 500, provenance.DesiredNumberOfPools)
+	assert.Equal(t, "1/10", provenance.Pools[poolID].PoolParameters.Margin.String())
+
+	utxos, err := client.Utxos(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.Len(t, utxos, 1)
+	assert.Equal(t, hash, utxos[0].TxIn.TxHash)
+}
+
+func TestClient_BlockHeightOrigin(t *testing.T) {
+	client, _ := queryServer(t, map[string]string{"blockHeight": `"origin"`})
+	height, err := client.BlockHeight(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	assert.EqualValues(t, 0, height)
+}
@@ -31,6 +31,22 @@ type Store interface {
 	Load(ctx context.Context) (chainsync.Points, error)
 }
 
+// TruncateStore is implemented by stores that can discard points after a
+// rollback.  ChainSync invokes Truncate when it observes a RollBackward
+type TruncateStore interface {
+	Store
+	// Truncate removes all saved points after the provided point
+	Truncate(ctx context.Context, point chainsync.Point) error
+}
+
+// FlushStore is implemented by stores that batch saves.  ChainSync invokes
+// Flush after saving its final checkpoint
+type FlushStore interface {
+	Store
+	// Flush persists any batched saves
+	Flush(ctx context.Context) error
+}
+
 type loggingStore struct {
 	logger Logger
 }
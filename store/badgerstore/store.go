@@ -15,30 +15,85 @@
 package badgerstore
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
 
 	"github.com/dgraph-io/badger/v3"
 
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
 )
 
+// slotSegment separates the cursor name from the slot index in each key
+const slotSegment = "slot/"
+
+// Store persists points for a named cursor, indexed by slot
 type Store struct {
-	db      *badger.DB
-	counter int64
-	prefix  []byte
+	db        *badger.DB
+	prefix    []byte
+	history   int
+	syncEvery int
+
+	mutex    sync.Mutex
+	unsynced int
+	migrated bool
+}
+
+// Option to badger store
+type Option func(*Store)
+
+// WithHistory specifies the number of points to retain; defaults to 10
+func WithHistory(n int) Option {
+	return func(s *Store) {
+		s.history = n
+	}
 }
 
-func New(db *badger.DB, prefix string) *Store {
-	return &Store{
+// WithSyncEvery specifies how many saves may be batched before the db is
+// synced to disk; defaults to 1, sync on every save.  Store implements
+// ogmigo.FlushStore so ChainSync syncs batched saves when it stops
+func WithSyncEvery(n int) Option {
+	return func(s *Store) {
+		s.syncEvery = n
+	}
+}
+
+// New returns a Store for the cursor identified by prefix
+func New(db *badger.DB, prefix string, opts ...Option) *Store {
+	s := &Store{
 		db:     db,
 		prefix: []byte(strings.TrimRight(prefix, "/") + "/"),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.history <= 0 {
+		s.history = 10
+	}
+	if s.syncEvery <= 0 {
+		s.syncEvery = 1
+	}
+	return s
+}
+
+func (s *Store) slotPrefix() []byte {
+	return append(append([]byte{}, s.prefix...), slotSegment...)
+}
+
+func (s *Store) slotKey(slot uint64) []byte {
+	return append(s.slotPrefix(), fmt.Sprintf("%020d", slot)...)
+}
+
+func getSlot(point chainsync.Point) uint64 {
+	if ps, ok := point.PointStruct(); ok {
+		return ps.Slot
+	}
+	return 0
 }
 
 // Save the point; save will be called multiple times and should only
@@ -49,39 +104,247 @@ func (s *Store) Save(_ context.Context, point chainsync.Point) error {
 		return fmt.Errorf("failed to save point: %w", err)
 	}
 
-	v := atomic.AddInt64(&s.counter, 1) % 10
-	key := append(s.prefix, []byte(strconv.FormatInt(v, 10))...)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(s.slotKey(getSlot(point)), data); err != nil {
+			return fmt.Errorf("set failed: %w", err)
+		}
+		return s.prune(txn)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
 
-	tx := s.db.NewTransaction(true)
-	if err := tx.Set(key, data); err != nil {
-		return fmt.Errorf("failed to save point: set failed: %w", err)
+	s.unsynced++
+	if s.unsynced < s.syncEvery {
+		return nil
 	}
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to save point: commit failed: %w", err)
+	return s.sync()
+}
+
+// prune removes all but the most recent points
+func (s *Store) prune(txn *badger.Txn) error {
+	keys := s.keys(txn, nil)
+	if len(keys) <= s.history {
+		return nil
 	}
 
-	return s.db.Sync()
+	for _, key := range keys[:len(keys)-s.history] {
+		if err := txn.Delete(key); err != nil {
+			return fmt.Errorf("delete failed: %w", err)
+		}
+	}
+	return nil
 }
 
-// Load saved points
-func (s *Store) Load(context.Context) (chainsync.Points, error) {
-	tx := s.db.NewTransaction(false)
-	iter := tx.NewIterator(badger.DefaultIteratorOptions)
+// keys returns the slot index keys for this cursor, in slot order, starting
+// at from; nil from returns all keys
+func (s *Store) keys(txn *badger.Txn, from []byte) [][]byte {
+	iter := txn.NewIterator(badger.IteratorOptions{})
 	defer iter.Close()
 
+	prefix := s.slotPrefix()
+	if from == nil {
+		from = prefix
+	}
+
+	var keys [][]byte
+	for iter.Seek(from); iter.ValidForPrefix(prefix); iter.Next() {
+		keys = append(keys, iter.Item().KeyCopy(nil))
+	}
+	return keys
+}
+
+func (s *Store) sync() error {
+	if err := s.db.Sync(); err != nil {
+		return fmt.Errorf("failed to sync points: %w", err)
+	}
+	s.unsynced = 0
+	return nil
+}
+
+// Flush syncs any batched saves to disk
+func (s *Store) Flush(_ context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.unsynced == 0 {
+		return nil
+	}
+	return s.sync()
+}
+
+// Load saved points
+func (s *Store) Load(ctx context.Context) (chainsync.Points, error) {
+	if err := s.migrate(ctx); err != nil {
+		return nil, err
+	}
+
 	var pp chainsync.Points
-	for iter.Seek(s.prefix); iter.ValidForPrefix(s.prefix); iter.Next() {
-		var p chainsync.Point
-		unmarshal := func(val []byte) error { return json.Unmarshal(val, &p) }
+	err := s.db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
 
-		if err := iter.Item().Value(unmarshal); err != nil {
-			return nil, fmt.Errorf("failed to load points: %w", err)
+		prefix := s.slotPrefix()
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			var p chainsync.Point
+			unmarshal := func(val []byte) error { return json.Unmarshal(val, &p) }
+			if err := iter.Item().Value(unmarshal); err != nil {
+				return err
+			}
+			pp = append(pp, p)
 		}
-
-		pp = append(pp, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load points: %w", err)
 	}
 
 	sort.Sort(pp)
 
 	return pp, nil
 }
+
+// Truncate removes all points with a slot after the provided point; used to
+// discard points that are no longer on chain after a rollback
+func (s *Store) Truncate(_ context.Context, point chainsync.Point) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.db.Update(func(txn *badger.Txn) error {
+		var from []byte // origin; remove everything
+		if ps, ok := point.PointStruct(); ok {
+			from = s.slotKey(ps.Slot + 1)
+		}
+		for _, key := range s.keys(txn, from) {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to truncate points: %w", err)
+	}
+
+	return s.sync()
+}
+
+// Migrate moves points written by earlier versions of Store, which used the
+// keys {prefix}/0 through {prefix}/9, into the slot index
+func (s *Store) Migrate(ctx context.Context) error {
+	s.mutex.Lock()
+	s.migrated = false
+	s.mutex.Unlock()
+
+	return s.migrate(ctx)
+}
+
+func (s *Store) migrate(_ context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.migrated {
+		return nil
+	}
+
+	var found bool
+	err := s.db.Update(func(txn *badger.Txn) error {
+		for i := 0; i < 10; i++ {
+			key := append(append([]byte{}, s.prefix...), strconv.Itoa(i)...)
+			item, err := txn.Get(key)
+			if err == badger.ErrKeyNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			data, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			var p chainsync.Point
+			if err := json.Unmarshal(data, &p); err != nil {
+				return fmt.Errorf("failed to decode legacy point, %v: %w", string(key), err)
+			}
+			if err := txn.Set(s.slotKey(getSlot(p)), data); err != nil {
+				return err
+			}
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+			found = true
+		}
+		if !found {
+			return nil
+		}
+		return s.prune(txn)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate points: %w", err)
+	}
+	if found {
+		if err := s.sync(); err != nil {
+			return err
+		}
+	}
+
+	s.migrated = true
+	return nil
+}
+
+// List returns the names of all cursors stored under the provided prefix,
+// including cursors whose points have not yet been migrated
+func List(db *badger.DB, prefix string) ([]string, error) {
+	var (
+		seen    = map[string]struct{}{}
+		cursors []string
+		segment = []byte("/" + slotSegment)
+	)
+
+	err := db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.IteratorOptions{})
+		defer iter.Close()
+
+		p := []byte(prefix)
+		for iter.Seek(p); iter.ValidForPrefix(p); iter.Next() {
+			key := iter.Item().Key()
+			index := bytes.LastIndex(key, segment)
+			if index < 0 {
+				index = legacyIndex(key)
+			}
+			if index < 0 {
+				continue
+			}
+
+			cursor := string(key[:index])
+			if _, ok := seen[cursor]; ok {
+				continue
+			}
+			seen[cursor] = struct{}{}
+			cursors = append(cursors, cursor)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cursors: %w", err)
+	}
+
+	sort.Strings(cursors)
+
+	return cursors, nil
+}
+
+// legacyIndex returns the index of the cursor name terminator in a legacy key,
+// {cursor}/0 through {cursor}/9; -1 if key is not a legacy key
+func legacyIndex(key []byte) int {
+	n := len(key)
+	if n < 2 || key[n-2] != '/' || key[n-1] < '0' || key[n-1] > '9' {
+		return -1
+	}
+	return n - 2
+}
@@ -16,6 +16,7 @@ package badgerstore
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -23,14 +24,20 @@ import (
 	"github.com/dgraph-io/badger/v3"
 )
 
-func TestStore_Load(t *testing.T) {
-	db, err := badger.Open(badger.DefaultOptions("test-db"))
+func openDB(t *testing.T) *badger.DB {
+	db, err := badger.Open(badger.DefaultOptions(t.TempDir()).WithLoggingLevel(badger.WARNING))
 	if err != nil {
 		t.Fatalf("got %v; want nil", err)
 	}
-	defer db.Close()
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStore_Load(t *testing.T) {
+	db := openDB(t)
 
 	var (
+		err   error
 		ctx   = context.Background()
 		a     = chainsync.PointStruct{Slot: 10}
 		b     = chainsync.PointStruct{Slot: 20}
@@ -65,3 +72,142 @@ func TestStore_Load(t *testing.T) {
 		t.Fatalf("got %#v; want %#v", got, want)
 	}
 }
+
+func TestStore_History(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = New(openDB(t), "points", WithHistory(3), WithSyncEvery(2))
+	)
+
+	for _, slot := range []uint64{50, 10, 40, 20, 30} {
+		if err := store.Save(ctx, chainsync.PointStruct{Slot: slot}.Point()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+	if err := store.Flush(ctx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	points, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := chainsync.Points{
+		chainsync.PointStruct{Slot: 50}.Point(),
+		chainsync.PointStruct{Slot: 40}.Point(),
+		chainsync.PointStruct{Slot: 30}.Point(),
+	}
+	if got := points; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestStore_Truncate(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = New(openDB(t), "points")
+	)
+
+	for _, slot := range []uint64{10, 20, 30} {
+		if err := store.Save(ctx, chainsync.PointStruct{Slot: slot}.Point()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+
+	if err := store.Truncate(ctx, chainsync.PointStruct{Slot: 20}.Point()); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	points, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := chainsync.Points{
+		chainsync.PointStruct{Slot: 20}.Point(),
+		chainsync.PointStruct{Slot: 10}.Point(),
+	}
+	if got := points; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if err := store.Truncate(ctx, chainsync.Origin); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	points, err = store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(points), 0; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestList(t *testing.T) {
+	var (
+		ctx = context.Background()
+		db  = openDB(t)
+	)
+
+	for _, cursor := range []string{"app/b", "app/a", "other"} {
+		if err := New(db, cursor).Save(ctx, chainsync.PointStruct{Slot: 1}.Point()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+
+	// written by an earlier version and not yet migrated
+	err := db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte("app/c/0"), []byte(`{"slot":1,"hash":"ab"}`))
+	})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	cursors, err := List(db, "app/")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := cursors, []string{"app/a", "app/b", "app/c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestStore_Migrate(t *testing.T) {
+	var (
+		ctx = context.Background()
+		db  = openDB(t)
+		a   = chainsync.PointStruct{Slot: 10}
+		b   = chainsync.PointStruct{Slot: 20}
+	)
+
+	err := db.Update(func(txn *badger.Txn) error {
+		for key, p := range map[string]chainsync.PointStruct{"points/3": b, "points/4": a} {
+			data, err := json.Marshal(p.Point())
+			if err != nil {
+				return err
+			}
+			if err := txn.Set([]byte(key), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	points, err := New(db, "points").Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := chainsync.Points{b.Point(), a.Point()}
+	if got := points; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	err = db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte("points/3"))
+		return err
+	})
+	if err != badger.ErrKeyNotFound {
+		t.Fatalf("got %v; want %v", err, badger.ErrKeyNotFound)
+	}
+}
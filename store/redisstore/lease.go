@@ -0,0 +1,158 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/SundaeSwap-finance/ogmigo"
+)
+
+var (
+	acquireScript = redis.NewScript(`
+local v = redis.call("GET", KEYS[1])
+if v == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+if v == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// Lease implements ogmigo.Lease using a redis key holding a random token
+type Lease struct {
+	client redis.UniversalClient
+	key    string
+	token  string
+	ttl    time.Duration
+	poll   time.Duration
+}
+
+// LeaseOption to redis lease
+type LeaseOption func(*Lease)
+
+// WithTTL specifies how long the lease is held without renewal; defaults to 15s.
+// The interval passed to ogmigo.WithLease must be shorter than the ttl
+func WithTTL(ttl time.Duration) LeaseOption {
+	return func(l *Lease) {
+		l.ttl = ttl
+	}
+}
+
+// WithPollInterval specifies how frequently a waiting replica attempts to
+// acquire the lease; defaults to a third of the ttl
+func WithPollInterval(interval time.Duration) LeaseOption {
+	return func(l *Lease) {
+		l.poll = interval
+	}
+}
+
+// WithToken specifies the token identifying the holder; defaults to a random value
+func WithToken(token string) LeaseOption {
+	return func(l *Lease) {
+		l.token = token
+	}
+}
+
+// NewLease returns a lease stored under the provided key
+func NewLease(client redis.UniversalClient, key string, opts ...LeaseOption) *Lease {
+	l := &Lease{
+		client: client,
+		key:    key,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.ttl <= 0 {
+		l.ttl = 15 * time.Second
+	}
+	if l.poll <= 0 {
+		l.poll = l.ttl / 3
+	}
+	if l.token == "" {
+		data := make([]byte, 16)
+		_, _ = rand.Read(data)
+		l.token = hex.EncodeToString(data)
+	}
+	return l
+}
+
+// Acquire blocks until the lease is held or the context is cancelled
+func (l *Lease) Acquire(ctx context.Context) error {
+	for {
+		ok, err := acquireScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Bool()
+		if err != nil {
+			return fmt.Errorf("failed to acquire lease, %v: %w", l.key, err)
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.poll):
+		}
+	}
+}
+
+// TTL returns how long the lease is held without renewal
+func (l *Lease) TTL() time.Duration {
+	return l.ttl
+}
+
+// Renew extends the lease; returns ogmigo.ErrLeaseLost if the lease is held by another
+func (l *Lease) Renew(ctx context.Context) error {
+	ok, err := renewScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Bool()
+	if err != nil {
+		return fmt.Errorf("failed to renew lease, %v: %w", l.key, err)
+	}
+	if !ok {
+		return fmt.Errorf("failed to renew lease, %v: %w", l.key, ogmigo.ErrLeaseLost)
+	}
+	return nil
+}
+
+// Release gives up the lease if held
+func (l *Lease) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lease, %v: %w", l.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/ogmigo"
+)
+
+func TestLease(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = newClient(t)
+		store  = New(client, "points")
+		leader = store.Lease(WithToken("leader"))
+		waiter = store.Lease(WithToken("waiter"), WithPollInterval(time.Millisecond))
+	)
+
+	var lease ogmigo.TTLLease = leader
+	if got, want := lease.TTL(), 15*time.Second; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if err := leader.Acquire(ctx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := leader.Acquire(ctx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := waiter.Acquire(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v; want %v", err, context.DeadlineExceeded)
+	}
+	if err := waiter.Renew(ctx); !errors.Is(err, ogmigo.ErrLeaseLost) {
+		t.Fatalf("got %v; want %v", err, ogmigo.ErrLeaseLost)
+	}
+
+	if err := leader.Renew(ctx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := leader.Release(ctx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	if err := waiter.Acquire(ctx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := leader.Renew(ctx); !errors.Is(err, ogmigo.ErrLeaseLost) {
+		t.Fatalf("got %v; want %v", err, ogmigo.ErrLeaseLost)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+// Store persists points to a redis sorted set scored by slot
+type Store struct {
+	client  redis.UniversalClient
+	key     string
+	history int64
+}
+
+// Option to redis store
+type Option func(*Store)
+
+// WithHistory specifies the number of points to retain; defaults to 10
+func WithHistory(n int) Option {
+	return func(s *Store) {
+		s.history = int64(n)
+	}
+}
+
+// New returns a Store that saves points to the sorted set identified by key
+func New(client redis.UniversalClient, key string, opts ...Option) *Store {
+	s := &Store{
+		client: client,
+		key:    key,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.history <= 0 {
+		s.history = 10
+	}
+	return s
+}
+
+// Save the point; save will be called multiple times and should only
+// keep track of the most recent points
+func (s *Store) Save(ctx context.Context, point chainsync.Point) error {
+	data, err := json.Marshal(point)
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+
+	var score float64
+	if ps, ok := point.PointStruct(); ok {
+		score = float64(ps.Slot)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, s.key, redis.Z{Score: score, Member: data})
+		pipe.ZRemRangeByRank(ctx, s.key, 0, -s.history-1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+
+	return nil
+}
+
+// Load saved points
+func (s *Store) Load(ctx context.Context) (chainsync.Points, error) {
+	members, err := s.client.ZRevRange(ctx, s.key, 0, s.history-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load points: %w", err)
+	}
+
+	var pp chainsync.Points
+	for _, member := range members {
+		var p chainsync.Point
+		if err := json.Unmarshal([]byte(member), &p); err != nil {
+			return nil, fmt.Errorf("failed to load points: %w", err)
+		}
+		pp = append(pp, p)
+	}
+
+	sort.Sort(pp)
+
+	return pp, nil
+}
+
+// Lease returns a leader lease for the cursor held by this store
+func (s *Store) Lease(opts ...LeaseOption) *Lease {
+	return NewLease(s.client, s.key+":lease", opts...)
+}
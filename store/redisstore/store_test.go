@@ -0,0 +1,56 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+func newClient(t *testing.T) redis.UniversalClient {
+	server := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: server.Addr()})
+}
+
+func TestStore_Load(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		a     = chainsync.PointStruct{Slot: 10, Hash: "a"}
+		b     = chainsync.PointStruct{Slot: 20, Hash: "b"}
+		c     = chainsync.PointStruct{Slot: 30, Hash: "c"}
+		store = New(newClient(t), "points", WithHistory(2))
+	)
+
+	for _, p := range []chainsync.PointStruct{b, c, a} {
+		if err := store.Save(ctx, p.Point()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+
+	points, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := chainsync.Points{c.Point(), b.Point()}
+	if got := points; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
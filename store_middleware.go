@@ -0,0 +1,294 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+// truncate forwards the Truncate request to store if supported
+func truncate(ctx context.Context, store Store, point chainsync.Point) error {
+	if ts, ok := store.(TruncateStore); ok {
+		return ts.Truncate(ctx, point)
+	}
+	return nil
+}
+
+// MultiStoreError contains the errors returned by each failing store
+type MultiStoreError struct {
+	Errs []error
+}
+
+// Error implements the error interface
+func (m MultiStoreError) Error() string {
+	var ss []string
+	for _, err := range m.Errs {
+		ss = append(ss, err.Error())
+	}
+	return fmt.Sprintf("%v store(s) failed: %v", len(m.Errs), strings.Join(ss, "; "))
+}
+
+// Unwrap allows errors.Is and errors.As to inspect the first error
+func (m MultiStoreError) Unwrap() error {
+	if len(m.Errs) == 0 {
+		return nil
+	}
+	return m.Errs[0]
+}
+
+type multiStore struct {
+	logger Logger
+	stores []Store
+}
+
+// NewMultiStore returns a Store that saves points to every store and loads
+// the newest points held by any store.  Save succeeds when at least one store
+// saved the point; failures of the remaining stores are logged to logger,
+// DefaultLogger if nil.  Truncate must succeed on every store, otherwise a
+// store left ahead of the rollback would win the next Load
+func NewMultiStore(logger Logger, stores ...Store) TruncateStore {
+	if logger == nil {
+		logger = DefaultLogger
+	}
+	return &multiStore{
+		logger: logger,
+		stores: stores,
+	}
+}
+
+func (m *multiStore) each(fn func(store Store) error) error {
+	var errs []error
+	for i, store := range m.stores {
+		if err := fn(store); err != nil {
+			errs = append(errs, fmt.Errorf("store %v: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return MultiStoreError{Errs: errs}
+	}
+	return nil
+}
+
+func (m *multiStore) Save(ctx context.Context, point chainsync.Point) error {
+	err := m.each(func(store Store) error { return store.Save(ctx, point) })
+	var mse MultiStoreError
+	if errors.As(err, &mse) && len(mse.Errs) < len(m.stores) {
+		for _, e := range mse.Errs {
+			m.logger.Info("multi store failed to save point", KV("point", point.String()), KV("err", e.Error()))
+		}
+		return nil
+	}
+	return err
+}
+
+func (m *multiStore) Load(ctx context.Context) (chainsync.Points, error) {
+	var (
+		errs   []error
+		found  chainsync.Points
+		newest chainsync.Point
+	)
+	for i, store := range m.stores {
+		points, err := store.Load(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("store %v: %w", i, err))
+			continue
+		}
+		if point, ok := points.Newest(); ok && (found == nil || point.Compare(newest) > 0) {
+			found, newest = points, point
+		}
+	}
+	if found == nil && len(errs) == len(m.stores) && len(errs) > 0 {
+		return nil, MultiStoreError{Errs: errs}
+	}
+	for _, err := range errs {
+		m.logger.Info("multi store failed to load points", KV("err", err.Error()))
+	}
+	return found, nil
+}
+
+func (m *multiStore) Truncate(ctx context.Context, point chainsync.Point) error {
+	return m.each(func(store Store) error { return truncate(ctx, store, point) })
+}
+
+// WriteBehindStore coalesces frequent Save calls, writing only the most
+// recent point to the underlying store once per interval
+type WriteBehindStore struct {
+	store    Store
+	logger   Logger
+	interval time.Duration
+
+	flushMutex sync.Mutex // serializes writes to store
+	mutex      sync.Mutex // guards pending
+	pending    *chainsync.Point
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWriteBehindStore returns a Store that writes to store at most once per
+// interval, 5s if interval is not positive.  Close must be called to flush
+// the final point
+func NewWriteBehindStore(store Store, interval time.Duration, logger Logger) *WriteBehindStore {
+	if logger == nil {
+		logger = DefaultLogger
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &WriteBehindStore{
+		store:    store,
+		logger:   logger,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go w.run(ctx)
+
+	return w
+}
+
+func (w *WriteBehindStore) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Flush(ctx); err != nil {
+				w.logger.Info("write behind store failed to save point", KV("err", err.Error()))
+			}
+		}
+	}
+}
+
+// Flush writes the pending point, if any, to the underlying store.  Save is
+// not blocked while the write is in progress
+func (w *WriteBehindStore) Flush(ctx context.Context) error {
+	w.flushMutex.Lock()
+	defer w.flushMutex.Unlock()
+
+	w.mutex.Lock()
+	pending := w.pending
+	w.pending = nil
+	w.mutex.Unlock()
+
+	if pending == nil {
+		return nil
+	}
+	if err := w.store.Save(ctx, *pending); err != nil {
+		w.mutex.Lock()
+		if w.pending == nil {
+			w.pending = pending // keep for the next flush unless superseded
+		}
+		w.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Close stops the background writer and flushes the pending point
+func (w *WriteBehindStore) Close() error {
+	w.cancel()
+	<-w.done
+	return w.Flush(context.Background())
+}
+
+// Save records the point to be written on the next flush
+func (w *WriteBehindStore) Save(_ context.Context, point chainsync.Point) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending = &point
+	return nil
+}
+
+// Load flushes the pending point and loads from the underlying store
+func (w *WriteBehindStore) Load(ctx context.Context) (chainsync.Points, error) {
+	if err := w.Flush(ctx); err != nil {
+		return nil, err
+	}
+	return w.store.Load(ctx)
+}
+
+// Truncate discards the pending point if it follows point and forwards the
+// request to the underlying store
+func (w *WriteBehindStore) Truncate(ctx context.Context, point chainsync.Point) error {
+	w.flushMutex.Lock()
+	defer w.flushMutex.Unlock()
+
+	w.mutex.Lock()
+	if w.pending != nil {
+		ps, ok := point.PointStruct()
+		pending, _ := w.pending.PointStruct()
+		if !ok || (pending != nil && pending.Slot > ps.Slot) {
+			w.pending = nil
+		}
+	}
+	w.mutex.Unlock()
+
+	return truncate(ctx, w.store, point)
+}
+
+// StoreObserver receives the outcome of each store operation; op is one of
+// save, load or truncate
+type StoreObserver func(op string, elapsed time.Duration, err error)
+
+type instrumentedStore struct {
+	store    Store
+	observer StoreObserver
+}
+
+// NewInstrumentedStore returns a Store that reports the latency and result of
+// each operation to observer
+func NewInstrumentedStore(store Store, observer StoreObserver) TruncateStore {
+	return &instrumentedStore{
+		store:    store,
+		observer: observer,
+	}
+}
+
+func (i *instrumentedStore) Save(ctx context.Context, point chainsync.Point) error {
+	started := time.Now()
+	err := i.store.Save(ctx, point)
+	i.observer("save", time.Since(started), err)
+	return err
+}
+
+func (i *instrumentedStore) Load(ctx context.Context) (chainsync.Points, error) {
+	started := time.Now()
+	points, err := i.store.Load(ctx)
+	i.observer("load", time.Since(started), err)
+	return points, err
+}
+
+func (i *instrumentedStore) Truncate(ctx context.Context, point chainsync.Point) error {
+	started := time.Now()
+	err := truncate(ctx, i.store, point)
+	i.observer("truncate", time.Since(started), err)
+	return err
+}
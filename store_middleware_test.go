@@ -0,0 +1,268 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+type memStore struct {
+	mutex sync.Mutex
+	err   error
+	saves chainsync.Points
+}
+
+func (m *memStore) Save(_ context.Context, point chainsync.Point) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	m.saves = append(m.saves, point)
+	return nil
+}
+
+func (m *memStore) Load(context.Context) (chainsync.Points, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.saves, nil
+}
+
+func (m *memStore) Truncate(_ context.Context, point chainsync.Point) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ps, _ := point.PointStruct()
+	var saves chainsync.Points
+	for _, p := range m.saves {
+		if v, _ := p.PointStruct(); ps != nil && v.Slot <= ps.Slot {
+			saves = append(saves, p)
+		}
+	}
+	m.saves = saves
+	return nil
+}
+
+func TestMultiStore(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		boom    = errors.New("boom")
+		broken  = &memStore{err: boom}
+		healthy = &memStore{}
+		store   = NewMultiStore(NopLogger, broken, healthy)
+		point   = chainsync.PointStruct{Slot: 10}.Point()
+	)
+
+	err := store.Save(ctx, point)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	points, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := points, (chainsync.Points{point}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	err = NewMultiStore(NopLogger, broken).Save(ctx, point)
+	if !errors.Is(err, boom) {
+		t.Fatalf("got %v; want %v", err, boom)
+	}
+
+	_, err = NewMultiStore(NopLogger, broken).Load(ctx)
+	var mse MultiStoreError
+	if !errors.As(err, &mse) {
+		t.Fatalf("got %v; want MultiStoreError", err)
+	}
+}
+
+func TestMultiStore_LoadNewest(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		older  = chainsync.PointStruct{Slot: 10, Hash: "aa"}.Point()
+		newer  = chainsync.PointStruct{Slot: 20, Hash: "bb"}.Point()
+		empty  = &memStore{}
+		stale  = &memStore{saves: chainsync.Points{older}}
+		latest = &memStore{saves: chainsync.Points{newer, older}}
+		store  = NewMultiStore(NopLogger, empty, stale, latest)
+	)
+
+	points, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := points, latest.saves; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	points, err = NewMultiStore(NopLogger, empty).Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got := len(points); got != 0 {
+		t.Fatalf("got %v; want 0", got)
+	}
+}
+
+func TestWriteBehindStore(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		underlying = &memStore{}
+		store      = NewWriteBehindStore(underlying, time.Hour, NopLogger)
+	)
+
+	for _, slot := range []uint64{10, 20, 30} {
+		if err := store.Save(ctx, chainsync.PointStruct{Slot: slot}.Point()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+	if got, want := len(underlying.saves), 0; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if err := store.Truncate(ctx, chainsync.PointStruct{Slot: 20}.Point()); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := store.Save(ctx, chainsync.PointStruct{Slot: 25}.Point()); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := chainsync.Points{chainsync.PointStruct{Slot: 25}.Point()}
+	if got := underlying.saves; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+type blockingStore struct {
+	memStore
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingStore) Save(ctx context.Context, point chainsync.Point) error {
+	b.entered <- struct{}{}
+	<-b.release
+	return b.memStore.Save(ctx, point)
+}
+
+func TestWriteBehindStore_Flush(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save during flush", func(t *testing.T) {
+		underlying := &blockingStore{
+			entered: make(chan struct{}, 2),
+			release: make(chan struct{}),
+		}
+		store := NewWriteBehindStore(underlying, time.Hour, NopLogger)
+		defer store.Close()
+
+		_ = store.Save(ctx, chainsync.PointStruct{Slot: 10}.Point())
+
+		errs := make(chan error, 1)
+		go func() { errs <- store.Flush(ctx) }()
+		<-underlying.entered
+
+		saved := make(chan struct{})
+		go func() {
+			_ = store.Save(ctx, chainsync.PointStruct{Slot: 20}.Point())
+			close(saved)
+		}()
+		select {
+		case <-saved:
+		case <-time.After(time.Second):
+			t.Fatalf("got Save blocked by Flush; want not blocked")
+		}
+
+		close(underlying.release)
+		if err := <-errs; err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if err := store.Flush(ctx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		want := chainsync.Points{
+			chainsync.PointStruct{Slot: 10}.Point(),
+			chainsync.PointStruct{Slot: 20}.Point(),
+		}
+		if got := underlying.saves; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("failed flush keeps point", func(t *testing.T) {
+		underlying := &memStore{err: errors.New("boom")}
+		store := NewWriteBehindStore(underlying, 0, NopLogger)
+
+		_ = store.Save(ctx, chainsync.PointStruct{Slot: 10}.Point())
+		if err := store.Flush(ctx); err == nil {
+			t.Fatalf("got nil; want err")
+		}
+
+		underlying.mutex.Lock()
+		underlying.err = nil
+		underlying.mutex.Unlock()
+		if err := store.Close(); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		want := chainsync.Points{chainsync.PointStruct{Slot: 10}.Point()}
+		if got := underlying.saves; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		boom = errors.New("boom")
+		ops  []string
+		errs []error
+	)
+
+	observer := func(op string, elapsed time.Duration, err error) {
+		ops = append(ops, op)
+		errs = append(errs, err)
+	}
+	store := NewInstrumentedStore(&memStore{err: boom}, observer)
+	_ = store.Save(ctx, chainsync.Origin)
+	_, _ = store.Load(ctx)
+	_ = store.Truncate(ctx, chainsync.Origin)
+
+	if got, want := ops, []string{"save", "load", "truncate"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := errs, []error{boom, boom, nil}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}